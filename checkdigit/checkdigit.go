@@ -0,0 +1,145 @@
+// Package checkdigit implements the check digit algorithms of contract IDs
+package checkdigit
+
+import (
+	"errors"
+	"unicode/utf8"
+)
+
+// Algorithm is a check digit algorithm
+type Algorithm int
+
+const (
+	// None means that there is no check digit
+	None Algorithm = iota
+	// Iso is the algorithm of ISO 15118-1 and EMI3 contract IDs
+	Iso
+	// Din is the algorithm of DIN SPEC 91286 contract IDs
+	Din
+)
+
+// alphabet lists the characters codes are made of, in the order of their value
+const alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// values maps each byte to its value in alphabet, in any case, or -1 if not part of it
+var values [256]int8
+
+var errNoAlgorithm = errors.New("no check digit algorithm")
+
+func init() {
+	for i := range values {
+		values[i] = -1
+	}
+
+	for i := 0; i < len(alphabet); i++ {
+		values[alphabet[i]] = int8(i)
+		values[alphabet[i]|0x20] = int8(i) // lower case letters, harmless for digits which have the bit already set
+	}
+}
+
+// Compute computes the check digit of code, which is the concatenation of all the characters of an ID preceding its
+// check digit, without separators.
+// It runs in constant time per character, and it never allocates.
+func (a Algorithm) Compute(code string) (rune, error) {
+	d := a.NewDigest()
+	for _, r := range code {
+		if r >= utf8.RuneSelf {
+			// not part of any alphabet, written as a single, invalid, character
+			r = 0
+		}
+
+		if err := d.WriteByte(byte(r)); err != nil {
+			return -1, err
+		}
+	}
+
+	return d.Sum()
+}
+
+func (a Algorithm) String() string {
+	switch a {
+	case Iso:
+		return "ISO"
+	case Din:
+		return "DIN"
+	default:
+		return "none"
+	}
+}
+
+// NewDigest returns a Digest computing check digits with this algorithm
+func (a Algorithm) NewDigest() Digest {
+	return Digest{algorithm: a}
+}
+
+// Digest computes a check digit incrementally, from the characters of the code written one at a time, e.g. while
+// scanning an input, without building the code. It is a plain value, which never allocates.
+type Digest struct {
+	algorithm Algorithm
+	length    int
+	err       error
+
+	iso   isoSums
+	din   uint8
+	coeff int
+}
+
+// WriteByte writes the next character of the code; once it has returned an error, the digest keeps returning it.
+// It implements io.ByteWriter.
+func (d *Digest) WriteByte(b byte) error {
+	if d.err != nil {
+		return d.err
+	}
+
+	switch d.algorithm {
+	case Iso:
+		d.err = d.writeIso(b)
+	case Din:
+		d.writeDin(b)
+	default:
+		d.err = errNoAlgorithm
+	}
+
+	if d.err == nil {
+		d.length++
+	}
+
+	return d.err
+}
+
+// WriteString writes the next characters of the code, byte by byte. It implements io.StringWriter.
+func (d *Digest) WriteString(s string) (int, error) {
+	for i := 0; i < len(s); i++ {
+		if err := d.WriteByte(s[i]); err != nil {
+			return i, err
+		}
+	}
+
+	return len(s), nil
+}
+
+// Len returns the number of characters written so far
+func (d *Digest) Len() int {
+	return d.length
+}
+
+// Sum returns the check digit of the characters written so far, or -1 and an error if they are not a valid code
+func (d *Digest) Sum() (rune, error) {
+	if d.err != nil {
+		return -1, d.err
+	}
+
+	switch d.algorithm {
+	case Iso:
+		return d.sumIso()
+	case Din:
+		return d.sumDin(), nil
+	default:
+		return -1, errNoAlgorithm
+	}
+}
+
+// Reset discards the characters written so far
+func (d *Digest) Reset() {
+	*d = d.algorithm.NewDigest()
+}
@@ -0,0 +1,152 @@
+package checkdigit
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+func randomCode(r *rand.Rand, length int, characters string) string {
+	code := make([]byte, length)
+	for i := range code {
+		code[i] = characters[r.Intn(len(characters))]
+	}
+
+	return string(code)
+}
+
+func TestAlgorithm_Compute_Iso(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 10000; i++ {
+		code := randomCode(r, IsoLength, alphabet)
+		expected, _ := referenceIso(code)
+
+		actual, err := Iso.Compute(code)
+
+		assert.Nil(t, err)
+		assert.Equal(t, expected, actual, code)
+	}
+
+	for _, code := range []string{"", "DE8AA00123456", "DE8AA0012345678", "de8AA001234567", "DE8AA00123456Ä", "DE8AA00123456*"} {
+		actual, err := Iso.Compute(code)
+
+		assert.NotNil(t, err, code)
+		assert.Equal(t, rune(-1), actual, code)
+	}
+}
+
+func TestAlgorithm_Compute_Din(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 10000; i++ {
+		code := randomCode(r, r.Intn(20), alphabet+"abcxyz*-Ä")
+		actual, err := Din.Compute(code)
+
+		assert.Nil(t, err)
+		assert.Equal(t, referenceDin(code), actual, code)
+	}
+}
+
+func TestAlgorithm_Compute_None(t *testing.T) {
+	_, err := None.Compute("NLTNM")
+
+	assert.NotNil(t, err)
+}
+
+func TestDigest(t *testing.T) {
+	cases := []struct {
+		algorithm Algorithm
+		code      string
+		expected  rune
+	}{
+		{algorithm: Iso, code: "NLTNMC00122045", expected: 'K'},
+		{algorithm: Iso, code: "DE8AA001234567", expected: '0'},
+		{algorithm: Din, code: "INTNM000071", expected: '9'},
+		{algorithm: Din, code: "intnm000110", expected: 'X'},
+	}
+
+	for _, test := range cases {
+		t.Run(test.code, func(t *testing.T) {
+			d := test.algorithm.NewDigest()
+			for i := 0; i < len(test.code); i++ {
+				assert.Nil(t, d.WriteByte(test.code[i]))
+			}
+
+			actual, err := d.Sum()
+
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, actual)
+			assert.Equal(t, len(test.code), d.Len())
+
+			d.Reset()
+			n, err := d.WriteString(test.code)
+			actual, _ = d.Sum()
+
+			assert.Nil(t, err)
+			assert.Equal(t, len(test.code), n)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestDigest_Errors(t *testing.T) {
+	t.Run("keeps returning the error of an invalid character", func(t *testing.T) {
+		d := Iso.NewDigest()
+
+		n, err := d.WriteString("NL*TNM")
+
+		assert.Equal(t, 2, n)
+		assert.NotNil(t, err)
+		assert.Equal(t, err, d.WriteByte('T'))
+
+		_, sumErr := d.Sum()
+		assert.Equal(t, err, sumErr)
+	})
+
+	t.Run("returns an error if the code is too short or too long", func(t *testing.T) {
+		d := Iso.NewDigest()
+		_, _ = d.WriteString("NLTNMC0012204")
+
+		_, err := d.Sum()
+		assert.NotNil(t, err)
+
+		_, _ = d.WriteString("5")
+		assert.NotNil(t, d.WriteByte('5'))
+	})
+}
+
+func TestDigest_DoesNotAllocate(t *testing.T) {
+	assert.Zero(t, testing.AllocsPerRun(100, func() {
+		_, _ = Iso.Compute("NLTNMC00122045")
+		_, _ = Din.Compute("INTNM000071")
+	}))
+}
+
+func BenchmarkAlgorithm_Compute_Iso(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = Iso.Compute("NLTNMC00122045")
+	}
+}
+
+func BenchmarkReferenceIso(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = referenceIso("NLTNMC00122045")
+	}
+}
+
+func BenchmarkAlgorithm_Compute_Din(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = Din.Compute("INTNM000071")
+	}
+}
+
+func BenchmarkReferenceDin(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = referenceDin("INTNM000071")
+	}
+}
@@ -0,0 +1,54 @@
+package checkdigit
+
+// porting of https://github.com/ShellRechargeSolutionsEU/mobilityid/blob/master/core/src/main/scala/com/thenewmotion/mobilityid/checkDigit.scala
+//
+// The value of each character of the code is split into its decimal digits, each multiplied by the next power of 2;
+// the check digit is the sum of these products, modulo 11. Since 2^10 = 1 modulo 11, the contribution of every
+// character for every power of 2 is precomputed in dinTerms, modulo 11.
+
+// dinPeriod is the period of the powers of 2, modulo 11
+const dinPeriod = 10
+
+var dinTerms [dinPeriod][len(alphabet)]uint8
+
+func init() {
+	var powers [dinPeriod + 1]int
+	for i, power := 0, 1; i <= dinPeriod; i, power = i+1, power*2%11 {
+		powers[i] = power
+	}
+
+	for coeff := 0; coeff < dinPeriod; coeff++ {
+		for x := range alphabet {
+			term := x * powers[coeff]
+			if x >= 10 {
+				term = x/10*powers[coeff] + x%10*powers[coeff+1]
+			}
+
+			dinTerms[coeff][x] = uint8(term % 11)
+		}
+	}
+}
+
+// writeDin adds the term of character b, in any case, to the sum of d; characters other than ASCII letters and
+// decimal digits count as '0'.
+func (d *Digest) writeDin(b byte) {
+	value := values[b]
+	if value < 0 {
+		value = 0
+	}
+
+	d.din = (d.din + dinTerms[d.coeff][value]) % 11
+	d.coeff = (d.coeff + 1) % dinPeriod
+	if value >= 10 {
+		d.coeff = (d.coeff + 1) % dinPeriod
+	}
+}
+
+// sumDin returns the check digit from the sum of d
+func (d *Digest) sumDin() rune {
+	if d.din >= 10 {
+		return 'X'
+	}
+
+	return '0' + rune(d.din)
+}
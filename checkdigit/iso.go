@@ -0,0 +1,125 @@
+package checkdigit
+
+import (
+	"errors"
+	"fmt"
+)
+
+// porting of https://github.com/ShellRechargeSolutionsEU/mobilityid/blob/master/core/src/main/scala/com/thenewmotion/mobilityid/checkDigit.scala
+//
+// Each character of the code is encoded as a 2x2 matrix, whose rows are multiplied by the powers of p1 and p2 matching
+// its position and summed up; the check digit is then decoded from the sums, modulo 2 for p1 and modulo 3 for p2.
+// Since only those remainders matter, the contribution of every character at every position is precomputed in
+// isoTerms, and the decoding of every combination of remainders in isoDecoding.
+
+// IsoLength is the length of the codes ISO check digits are computed from
+const IsoLength = 14
+
+var (
+	negP2minus15 = matrix{0, 2, 2, 1} // -p2^(-15)
+
+	// cipher is the encoding of each character of alphabet, packed as described by decode
+	cipher = [len(alphabet)]int{
+		0, 16, 32, 4, 20, 36, 8, 24, 40, 2, // 0-9
+		18, 34, 6, 22, 38, 10, 26, 42, 1, 17, 33, 5, 21, // A-M
+		37, 9, 25, 41, 3, 19, 35, 7, 23, 39, 11, 27, 43, // N-Z
+	}
+
+	isoTerms    [IsoLength][len(alphabet)]isoSums
+	isoDecoding [44]byte
+
+	errIsoLength    = fmt.Errorf("code must have a length of %v", IsoLength)
+	errIsoCharacter = errors.New("code must consist of uppercase ASCII letters and digits only")
+)
+
+// isoSums are the sums of the rows of the encoded characters, multiplied by the powers of p1 (t1) and p2 (t2); terms
+// are reduced modulo 2 and 3 respectively, so that the sums of a whole code fit in a byte.
+type isoSums struct {
+	t11, t12, t21, t22 uint8
+}
+
+func (s isoSums) add(s2 isoSums) isoSums {
+	return isoSums{
+		t11: s.t11 + s2.t11,
+		t12: s.t12 + s2.t12,
+		t21: s.t21 + s2.t21,
+		t22: s.t22 + s2.t22,
+	}
+}
+
+func init() {
+	p1 := matrix{0, 1, 1, 1}
+	p2 := matrix{0, 1, 1, 2}
+
+	p1n, p2n := p1, p2
+	for i := 0; i < IsoLength; i++ {
+		for j, x := range cipher {
+			m := decode(x)
+			t1 := vec{m.m11, m.m12}.multiply(p1n)
+			t2 := vec{m.m21, m.m22}.multiply(p2n)
+
+			isoTerms[i][j] = isoSums{uint8(t1.v1 & 1), uint8(t1.v2 & 1), uint8(t2.v1 % 3), uint8(t2.v2 % 3)}
+		}
+
+		p1n = p1n.multiply(p1)
+		p2n = p2n.multiply(p2)
+	}
+
+	for j, x := range cipher {
+		isoDecoding[x] = alphabet[j]
+	}
+}
+
+type matrix struct {
+	m11, m12, m21, m22 int
+}
+
+func (m matrix) multiply(m2 matrix) matrix {
+	return matrix{
+		m11: m.m11*m2.m11 + m.m12*m2.m21,
+		m12: m.m11*m2.m12 + m.m12*m2.m22,
+		m21: m.m21*m2.m11 + m.m22*m2.m21,
+		m22: m.m21*m2.m12 + m.m22*m2.m22,
+	}
+}
+
+type vec struct {
+	v1, v2 int
+}
+
+func (v vec) multiply(m matrix) vec {
+	return vec{
+		v1: v.v1*m.m11 + v.v2*m.m21,
+		v2: v.v1*m.m12 + v.v2*m.m22,
+	}
+}
+
+func decode(x int) matrix {
+	return matrix{x & 1, (x >> 1) & 1, (x >> 2) & 3, x >> 4}
+}
+
+// writeIso adds the term of character b, at the current position, to the sums of d
+func (d *Digest) writeIso(b byte) error {
+	value := values[b]
+	if value < 0 || b >= 'a' && b <= 'z' {
+		return errIsoCharacter
+	}
+	if d.length >= IsoLength {
+		return errIsoLength
+	}
+
+	d.iso = d.iso.add(isoTerms[d.length][value])
+
+	return nil
+}
+
+// sumIso decodes the check digit from the sums of d
+func (d *Digest) sumIso() (rune, error) {
+	if d.length != IsoLength {
+		return -1, errIsoLength
+	}
+
+	t2m := vec{int(d.iso.t21), int(d.iso.t22)}.multiply(negP2minus15)
+
+	return rune(isoDecoding[int(d.iso.t11&1)|int(d.iso.t12&1)<<1|(t2m.v1%3)<<2|(t2m.v2%3)<<4]), nil
+}
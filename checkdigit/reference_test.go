@@ -0,0 +1,165 @@
+package checkdigit
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"unicode"
+)
+
+// porting of https://github.com/ShellRechargeSolutionsEU/mobilityid/blob/master/core/src/main/scala/com/thenewmotion/mobilityid/checkDigit.scala
+// Reference implementations, as straightforward as the original ones, to check the precomputed tables against.
+
+var (
+	refP1s []matrix
+	refP2s []matrix
+
+	refEncoding map[rune]matrix
+	refDecoding map[matrix]rune
+)
+
+func init() {
+	p1 := matrix{0, 1, 1, 1}
+	p2 := matrix{0, 1, 1, 2}
+
+	for i := 0; i < 14; i++ {
+		if i == 0 {
+			refP1s = append(refP1s, p1)
+			refP2s = append(refP2s, p2)
+		} else {
+			refP1s = append(refP1s, refP1s[i-1].multiply(p1))
+			refP2s = append(refP2s, refP2s[i-1].multiply(p2))
+		}
+	}
+
+	cipher := map[rune]int{
+		'0': 0, '1': 16, '2': 32,
+		'3': 4, '4': 20, '5': 36,
+		'6': 8, '7': 24, '8': 40,
+		'9': 2, 'A': 18, 'B': 34,
+		'C': 6, 'D': 22, 'E': 38,
+		'F': 10, 'G': 26, 'H': 42,
+		'I': 1, 'J': 17, 'K': 33,
+		'L': 5, 'M': 21, 'N': 37,
+		'O': 9, 'P': 25, 'Q': 41,
+		'R': 3, 'S': 19, 'T': 35,
+		'U': 7, 'V': 23, 'W': 39,
+		'X': 11, 'Y': 27, 'Z': 43,
+	}
+
+	refEncoding = make(map[rune]matrix)
+	refDecoding = make(map[matrix]rune)
+	for k, v := range cipher {
+		m := decode(v)
+
+		refEncoding[k] = m
+		refDecoding[m] = k
+	}
+}
+
+func (v vec) add(v2 vec) vec {
+	return vec{
+		v1: v.v1 + v2.v1,
+		v2: v.v2 + v2.v2,
+	}
+}
+
+// referenceIso is the straightforward porting of the ISO algorithm, to check the precomputed tables against.
+func referenceIso(code string) (rune, error) {
+	if len(code) != len(refP1s) {
+		return -1, fmt.Errorf("code must have a length of %v", len(refP1s))
+	}
+
+	for _, r := range code {
+		if !(unicode.IsUpper(r) || unicode.IsDigit(r)) || r > unicode.MaxASCII {
+			return -1, errors.New("code must consist of uppercase ASCII letters and digits only")
+		}
+	}
+
+	sumEq := func(ps []matrix, f func(matrix) vec) (vec, error) {
+		result := vec{}
+		for ix, p := range ps {
+			ch := code[ix]
+			mx, ok := refEncoding[rune(ch)]
+			if !ok {
+				return vec{}, fmt.Errorf("invalid character: %v", ch)
+			}
+
+			qr := f(mx)
+			result = result.add(qr.multiply(p))
+		}
+
+		return result, nil
+	}
+
+	t1, err := sumEq(refP1s, func(m matrix) vec {
+		return vec{
+			m.m11,
+			m.m12,
+		}
+	})
+	if err != nil {
+		return -1, fmt.Errorf("unable to compute check digit: %w", err)
+	}
+
+	t2, err := sumEq(refP2s, func(m matrix) vec {
+		return vec{
+			m.m21,
+			m.m22,
+		}
+	})
+	if err != nil {
+		return -1, fmt.Errorf("unable to compute check digit: %w", err)
+	}
+
+	t2m := t2.multiply(negP2minus15)
+
+	m15 := matrix{t1.v1 & 1, t1.v2 & 1, t2m.v1 % 3, t2m.v2 % 3}
+
+	if result, ok := refDecoding[m15]; ok {
+		return result, nil
+	}
+
+	return -1, fmt.Errorf("undecodable matrix: %v", m15)
+}
+
+var refNumericValues map[rune]int
+
+func init() {
+	refNumericValues = make(map[rune]int)
+	for i, x := range "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ" {
+		refNumericValues[x] = i
+	}
+}
+
+func refMult(value, coeff int) int {
+	return value * int(math.Pow(2, float64(coeff)))
+}
+
+// referenceDin is the straightforward porting of the DIN algorithm, to check the precomputed tables against.
+func referenceDin(code string) rune {
+	var sum, mod, coeff int
+
+	for _, r := range code {
+		if r >= 'a' && r <= 'z' {
+			r -= 'a' - 'A'
+		}
+
+		x := refNumericValues[r]
+		if x < 10 {
+			sum += refMult(x, coeff)
+			coeff++
+		} else {
+			sum += refMult(x/10, coeff) + refMult(x%10, coeff+1)
+			coeff += 2
+		}
+	}
+
+	mod = sum % 11
+
+	if mod >= 10 {
+		return 'X'
+	}
+
+	return '0' + rune(mod)
+}
@@ -0,0 +1,23 @@
+package common
+
+import "strings"
+
+// CallingCode returns the ITU calling code of the country whose ISO 3166-1 alpha-2 code is countryCode, in any case,
+// e.g. "+49" for "DE"; it returns "" if the country is unknown or has no calling code.
+//
+// Countries of the North American Numbering Plan all share "+1", and the ones using the numbering plan of another
+// country share its code, e.g. "+39" for Vatican City.
+func CallingCode(countryCode string) string {
+	country, _ := loadCountries().lookupAlpha2(countryCode)
+
+	return country.CallingCode
+}
+
+// CountriesByCallingCode returns the ISO 3166-1 alpha-2 codes of the countries using callingCode, whose leading '+' is
+// optional, sorted. A calling code may be shared by many countries, e.g. "+1" by Canada, the United States and the
+// other countries of the North American Numbering Plan.
+func CountriesByCallingCode(callingCode string) []string {
+	countries := loadCountries().byCallingCode["+"+strings.TrimPrefix(callingCode, "+")]
+
+	return append([]string(nil), countries...)
+}
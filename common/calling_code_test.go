@@ -0,0 +1,43 @@
+package common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCallingCode(t *testing.T) {
+	cases := []struct {
+		countryCode string
+		expected    string
+	}{
+		{"DE", "+49"},
+		{"nl", "+31"},
+		{"US", "+1"},
+		{"PR", "+1"},
+		{"KZ", "+7"},
+		{"VA", "+39"},
+		{"AQ", ""},
+		{"ZZ", ""},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.expected, CallingCode(tc.countryCode), tc.countryCode)
+	}
+}
+
+func TestCountriesByCallingCode(t *testing.T) {
+	assert.Equal(t, []string{"DE"}, CountriesByCallingCode("+49"))
+	assert.Equal(t, []string{"DE"}, CountriesByCallingCode("49"))
+	assert.Equal(t, []string{"KZ", "RU"}, CountriesByCallingCode("+7"))
+	assert.Contains(t, CountriesByCallingCode("+1"), "CA")
+	assert.Contains(t, CountriesByCallingCode("+1"), "US")
+	assert.Empty(t, CountriesByCallingCode("+999"))
+}
+
+func TestCallingCodes_AreWellFormed(t *testing.T) {
+	for _, country := range Countries() {
+		if country.CallingCode != "" {
+			assert.Regexp(t, `^\+[1-9][0-9]{0,2}$`, country.CallingCode, country.Alpha2)
+		}
+	}
+}
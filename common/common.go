@@ -2,22 +2,23 @@ package common
 
 import (
 	"fmt"
-	"github.com/pariz/gountries"
 	"regexp"
 	"strings"
 )
 
+// Deprecated: the syntax of each format is described by its grammar (see package grammar), e.g. grammar.CountryCode.
 const (
 	CountryCodeRegex = "([A-Za-z]{2})"
 	PartyCodeRegex   = "([A-Za-z0-9]{3})"
 	CheckDigitRegex  = "([A-Za-z0-9])"
 )
 
-var countries = gountries.New()
-
 // ExtractAndUpcaseGroup extracts a group (if found) and returns its value in upper case
+//
+// Deprecated: grammars (see package grammar) parse inputs into the values of their segments, in upper case, e.g.
+// with Grammar.Parse.
 func ExtractAndUpcaseGroup(re *regexp.Regexp, groups []string, name string, required bool) (string, error) {
-	if index := re.SubexpIndex(name); index < len(groups) {
+	if index := re.SubexpIndex(name); index >= 0 && index < len(groups) {
 		return strings.ToUpper(groups[index]), nil
 	}
 
@@ -28,10 +29,29 @@ func ExtractAndUpcaseGroup(re *regexp.Regexp, groups []string, name string, requ
 	return "", fmt.Errorf("group not found: %v", name)
 }
 
-// IsValidCountryCode validates if the country code exists
+// IsValidCountryCode validates if the country code, an ISO 3166-1 alpha-2 or alpha-3 code in any case, exists
 func IsValidCountryCode(code string) bool {
-	_, err := countries.FindCountryByAlpha(code)
+	if len(code) == 2 {
+		return IsKnownCountryCode(code)
+	}
+
+	country, ok := LookupCountry(code)
 
-	return err == nil
+	return ok && len(code) == 3 && strings.EqualFold(country.Alpha3, code)
 }
 
+// IsKnownCountryCode returns true if code, in any case, is the ISO 3166-1 alpha-2 code of an existing country.
+// Unlike IsValidCountryCode, it doesn't accept alpha-3 codes and it never allocates.
+func IsKnownCountryCode[T ~string | ~[]byte](code T) bool {
+	index := alpha2Index(code)
+
+	return index >= 0 && loadCountries().byAlpha2[index] != 0
+}
+
+func toUpper(b byte) byte {
+	if b >= 'a' && b <= 'z' {
+		return b - ('a' - 'A')
+	}
+
+	return b
+}
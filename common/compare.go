@@ -0,0 +1,44 @@
+package common
+
+// CompareFold compares a and b like strings.Compare, ignoring the case of ASCII letters as well as the characters in
+// ignored, e.g. separators. It doesn't allocate.
+func CompareFold(a, b, ignored string) int {
+	i, j := 0, 0
+	for {
+		for i < len(a) && isIgnored(a[i], ignored) {
+			i++
+		}
+		for j < len(b) && isIgnored(b[j], ignored) {
+			j++
+		}
+
+		switch {
+		case i == len(a) && j == len(b):
+			return 0
+		case i == len(a):
+			return -1
+		case j == len(b):
+			return 1
+		}
+
+		if x, y := toUpper(a[i]), toUpper(b[j]); x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+
+		i++
+		j++
+	}
+}
+
+func isIgnored(b byte, ignored string) bool {
+	for i := 0; i < len(ignored); i++ {
+		if ignored[i] == b {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,28 @@
+package common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCompareFold(t *testing.T) {
+	cases := []struct {
+		a, b     string
+		ignored  string
+		expected int
+	}{
+		{a: "NL", b: "NL", expected: 0},
+		{a: "nl", b: "NL", expected: 0},
+		{a: "DE", b: "NL", expected: -1},
+		{a: "NLX", b: "NL", expected: 1},
+		{a: "03*0", b: "030", ignored: "*", expected: 0},
+		{a: "03*0", b: "030", expected: -1},
+		{a: "*1", b: "02", ignored: "*", expected: 1},
+		{a: "", b: "", expected: 0},
+	}
+
+	for _, test := range cases {
+		assert.Equal(t, test.expected, CompareFold(test.a, test.b, test.ignored), "%q %q", test.a, test.b)
+		assert.Equal(t, -test.expected, CompareFold(test.b, test.a, test.ignored), "%q %q", test.b, test.a)
+	}
+}
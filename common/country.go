@@ -0,0 +1,197 @@
+package common
+
+import (
+	_ "embed"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Country is a country of ISO 3166-1
+type Country struct {
+	// Alpha2 is the alpha-2 code, e.g. "DE"
+	Alpha2 string
+	// Alpha3 is the alpha-3 code, e.g. "DEU"
+	Alpha3 string
+	// Numeric is the numeric code, made of 3 digits, e.g. "276"
+	Numeric string
+	// Name is the common English name, e.g. "Germany"
+	Name string
+	// CallingCode is the ITU calling code, e.g. "+49", or "" if the country has none (see CallingCode)
+	CallingCode string
+}
+
+// IsZero returns true if this is the zero value, i.e. not a known country
+func (c Country) IsZero() bool {
+	return c == Country{}
+}
+
+// String returns the alpha-2 code of the country
+func (c Country) String() string {
+	return c.Alpha2
+}
+
+// countriesData is the table of countries, described in its header
+//
+//go:embed countries.tsv
+var countriesData string
+
+// countryTable indexes countries by code. Alpha-2 codes index an array, so that checking them takes constant time
+// and never allocates; alpha-3, numeric and calling codes index maps.
+type countryTable struct {
+	countries     []Country
+	byAlpha2      [26 * 26]uint16 // position of each country in countries, plus one; 0 if unknown
+	byCode        map[string]uint16
+	byCallingCode map[string][]string
+	// primaries maps each calling code to the alpha-2 code of its primary country
+	primaries map[string]string
+}
+
+var (
+	countriesOnce sync.Once
+	countries     *countryTable
+)
+
+// loadCountries returns the table of countries, parsing it the first time it is needed
+func loadCountries() *countryTable {
+	countriesOnce.Do(func() {
+		table, err := parseCountries(countriesData)
+		if err != nil {
+			// the embedded table is verified by tests
+			panic(err)
+		}
+
+		countries = table
+	})
+
+	return countries
+}
+
+func parseCountries(data string) (*countryTable, error) {
+	table := &countryTable{
+		byCode:        map[string]uint16{},
+		byCallingCode: map[string][]string{},
+		primaries:     map[string]string{},
+	}
+
+	for i, line := range strings.Split(data, "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) != 5 {
+			return nil, fmt.Errorf("countries, line %d: expected 5 fields, got %d", i+1, len(fields))
+		}
+
+		// the primary country of a shared calling code is marked by a trailing '!'
+		callingCode := strings.TrimSuffix(fields[3], "!")
+		primary := callingCode != fields[3]
+		country := Country{
+			Alpha2: fields[0], Alpha3: fields[1], Numeric: fields[2], CallingCode: callingCode, Name: fields[4],
+		}
+		index := alpha2Index(country.Alpha2)
+		switch {
+		case index < 0:
+			return nil, fmt.Errorf("countries, line %d: invalid alpha-2 code %q", i+1, country.Alpha2)
+		case table.byAlpha2[index] != 0:
+			return nil, fmt.Errorf("countries, line %d: duplicate alpha-2 code %q", i+1, country.Alpha2)
+		}
+
+		table.countries = append(table.countries, country)
+		position := uint16(len(table.countries))
+		table.byAlpha2[index] = position
+
+		for _, code := range []string{country.Alpha3, country.Numeric} {
+			if _, ok := table.byCode[code]; ok {
+				return nil, fmt.Errorf("countries, line %d: duplicate code %q", i+1, code)
+			}
+			table.byCode[code] = position
+		}
+
+		if country.CallingCode != "" {
+			table.byCallingCode[country.CallingCode] = append(table.byCallingCode[country.CallingCode], country.Alpha2)
+		}
+
+		if primary {
+			if previous, ok := table.primaries[country.CallingCode]; ok {
+				return nil, fmt.Errorf(
+					"countries, line %d: %v is already the primary country of %q", i+1, previous, country.CallingCode)
+			}
+			table.primaries[country.CallingCode] = country.Alpha2
+		}
+	}
+
+	for callingCode, codes := range table.byCallingCode {
+		sort.Strings(codes)
+
+		if _, ok := table.primaries[callingCode]; !ok {
+			if len(codes) > 1 {
+				return nil, fmt.Errorf(
+					"countries: calling code %q is shared by %v, but none is marked as primary", callingCode, codes)
+			}
+			table.primaries[callingCode] = codes[0]
+		}
+	}
+
+	return table, nil
+}
+
+// alpha2Index returns the position of an alpha-2 code, in upper case, in countryTable.byAlpha2, or -1 if it isn't made
+// of 2 letters
+func alpha2Index[T ~string | ~[]byte](code T) int {
+	if len(code) != 2 {
+		return -1
+	}
+
+	a, b := toUpper(code[0]), toUpper(code[1])
+	if a < 'A' || a > 'Z' || b < 'A' || b > 'Z' {
+		return -1
+	}
+
+	return int(a-'A')*26 + int(b-'A')
+}
+
+// lookupAlpha2 returns the country whose alpha-2 code is code, in any case
+func (t *countryTable) lookupAlpha2(code string) (Country, bool) {
+	if index := alpha2Index(code); index >= 0 && t.byAlpha2[index] != 0 {
+		return t.countries[t.byAlpha2[index]-1], true
+	}
+
+	return Country{}, false
+}
+
+// LookupCountry returns the country whose ISO 3166-1 alpha-2, alpha-3 or numeric code is code, in any case
+func LookupCountry(code string) (Country, bool) {
+	table := loadCountries()
+	if len(code) == 2 {
+		return table.lookupAlpha2(code)
+	}
+
+	if position, ok := table.byCode[strings.ToUpper(code)]; ok {
+		return table.countries[position-1], true
+	}
+
+	return Country{}, false
+}
+
+// Countries returns all countries, sorted by alpha-2 code
+func Countries() []Country {
+	return append([]Country(nil), loadCountries().countries...)
+}
+
+// CountryOf returns the country of an identifier country code: an alpha-2 code, or the ITU calling code of DIN EVSE IDs
+// (e.g. "+49"). A calling code shared by many countries, which are listed by CountriesByCallingCode, resolves to its
+// primary country, e.g. the United States for "+1" and Italy for "+39". It returns the zero Country if the code is
+// unknown.
+func CountryOf(code string) Country {
+	table := loadCountries()
+	if strings.HasPrefix(code, "+") {
+		code = table.primaries[code]
+	}
+
+	country, _ := table.lookupAlpha2(code)
+
+	return country
+}
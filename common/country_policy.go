@@ -0,0 +1,181 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Regions, as lists of ISO 3166-1 alpha-2 codes, to be used as CountryPolicy.Allowed
+var (
+	// EU lists the member states of the European Union
+	EU = []string{
+		"AT", "BE", "BG", "CY", "CZ", "DE", "DK", "EE", "ES", "FI", "FR", "GR", "HR", "HU",
+		"IE", "IT", "LT", "LU", "LV", "MT", "NL", "PL", "PT", "RO", "SE", "SI", "SK",
+	}
+	// EEA lists the members of the European Economic Area: the member states of the European Union, Iceland,
+	// Liechtenstein and Norway
+	EEA = []string{
+		"AT", "BE", "BG", "CY", "CZ", "DE", "DK", "EE", "ES", "FI", "FR", "GR", "HR", "HU",
+		"IE", "IT", "LT", "LU", "LV", "MT", "NL", "PL", "PT", "RO", "SE", "SI", "SK",
+		"IS", "LI", "NO",
+	}
+)
+
+// Kosovo is the country of the user-assigned code "XK", which isn't part of ISO 3166-1 but is used by the European
+// Commission and by many roaming partners
+var Kosovo = Country{Alpha2: "XK", Alpha3: "XKX", Name: "Kosovo", CallingCode: "+383"}
+
+// Aliases maps well-known codes, which aren't ISO 3166-1 alpha-2 codes, to the ones of their country: "UK" for the
+// United Kingdom, and "EL" for Greece, as used by the European Union.
+var Aliases = map[string]string{
+	"UK": "GB",
+	"EL": "GR",
+}
+
+// CountryPolicy decides which country codes are accepted, beyond checking that they are ISO 3166-1 alpha-2 codes.
+// The zero value accepts all the countries of ISO 3166-1, and nothing else. Codes are matched in any case.
+type CountryPolicy struct {
+	// Allowed lists the alpha-2 codes of the only accepted countries, e.g. EEA; all countries are accepted if empty.
+	Allowed []string
+	// UserAssigned lists countries whose codes are user-assigned or transitional, e.g. Kosovo, accepted as if they
+	// were part of ISO 3166-1
+	UserAssigned []Country
+	// Aliases maps codes which aren't alpha-2 codes to the ones of their country, e.g. Aliases; an alias is accepted if
+	// its country is.
+	Aliases map[string]string
+	// Test lists codes used by test or sandbox IDs, e.g. "ZZ", which are always accepted and flagged by IsTest
+	Test []string
+}
+
+// Resolve returns the country of code: the ISO 3166-1 or user-assigned country it is the code of, or the one it is an
+// alias of. It returns a *FieldError, matching ErrUnknownCountry if code is unknown, or ErrCountryNotAllowed if its
+// country isn't allowed. Test codes resolve to a Country of which only Alpha2 is set.
+func (p *CountryPolicy) Resolve(code string) (Country, error) {
+	if p.IsTest(code) {
+		return Country{Alpha2: strings.ToUpper(code)}, nil
+	}
+
+	country, known := p.lookup(code)
+	if !known {
+		return Country{}, &FieldError{Field: FieldCountryCode, Value: strings.ToUpper(code), Err: ErrUnknownCountry}
+	}
+
+	if !p.allows(country.Alpha2) {
+		return Country{}, &FieldError{Field: FieldCountryCode, Value: strings.ToUpper(code), Err: ErrCountryNotAllowed}
+	}
+
+	return country, nil
+}
+
+// Check returns the error Resolve would return for code
+func (p *CountryPolicy) Check(code string) error {
+	_, err := p.Resolve(code)
+	return err
+}
+
+// CheckCallingCode returns the error ResolveCallingCode would return for callingCode
+func (p *CountryPolicy) CheckCallingCode(callingCode string) error {
+	_, err := p.ResolveCallingCode(callingCode)
+	return err
+}
+
+// ResolveCallingCode returns the country of the ITU calling code of a DIN EVSE ID, e.g. "+49", whose leading '+' is
+// optional. It is accepted if any of the countries using it is, and resolves to its primary country if allowed (see
+// CountryOf), to the first allowed one otherwise. Test codes resolve to a Country of which only CallingCode is set.
+func (p *CountryPolicy) ResolveCallingCode(callingCode string) (Country, error) {
+	callingCode = "+" + strings.TrimPrefix(callingCode, "+")
+	if p.IsTest(callingCode) || p.IsTest(callingCode[1:]) {
+		return Country{CallingCode: callingCode}, nil
+	}
+
+	table := loadCountries()
+	if primary := table.primaries[callingCode]; primary != "" && p.allows(primary) {
+		country, _ := table.lookupAlpha2(primary)
+		return country, nil
+	}
+
+	countries := table.byCallingCode[callingCode]
+	for _, code := range countries {
+		if p.allows(code) {
+			country, _ := table.lookupAlpha2(code)
+			return country, nil
+		}
+	}
+
+	for _, country := range p.UserAssigned {
+		if country.CallingCode != callingCode {
+			continue
+		}
+
+		if p.allows(country.Alpha2) {
+			return country, nil
+		}
+		countries = append(countries[:len(countries):len(countries)], country.Alpha2)
+	}
+
+	if len(countries) == 0 {
+		return Country{}, &FieldError{Field: FieldCountryCode, Value: callingCode, Err: ErrUnknownCountry}
+	}
+
+	return Country{}, &FieldError{
+		Field: FieldCountryCode,
+		Value: callingCode,
+		Err:   fmt.Errorf("%w: %v is used by %v", ErrCountryNotAllowed, callingCode, strings.Join(countries, ", ")),
+	}
+}
+
+// Accepting returns a policy accepting code, and nothing else, as the code of country, or nil if country is zero. It
+// validates again IDs whose country code has been resolved to country by another policy, when parsed.
+func Accepting(code string, country Country) *CountryPolicy {
+	if country.IsZero() {
+		return nil
+	}
+
+	policy := &CountryPolicy{Allowed: []string{country.Alpha2}, UserAssigned: []Country{country}}
+	if !strings.EqualFold(code, country.Alpha2) {
+		policy.Aliases = map[string]string{code: country.Alpha2}
+	}
+
+	return policy
+}
+
+// IsTest returns true if code is a test or sandbox code
+func (p *CountryPolicy) IsTest(code string) bool {
+	return containsFold(p.Test, code)
+}
+
+// lookup returns the country of code, following aliases, whether it is allowed or not
+func (p *CountryPolicy) lookup(code string) (Country, bool) {
+	for alias, target := range p.Aliases {
+		if strings.EqualFold(alias, code) {
+			code = target
+			break
+		}
+	}
+
+	for _, country := range p.UserAssigned {
+		if strings.EqualFold(country.Alpha2, code) {
+			return country, true
+		}
+	}
+
+	if len(code) != 2 {
+		return Country{}, false
+	}
+
+	return loadCountries().lookupAlpha2(code)
+}
+
+func (p *CountryPolicy) allows(alpha2 string) bool {
+	return len(p.Allowed) == 0 || containsFold(p.Allowed, alpha2)
+}
+
+func containsFold(codes []string, code string) bool {
+	for _, candidate := range codes {
+		if strings.EqualFold(candidate, code) {
+			return true
+		}
+	}
+
+	return false
+}
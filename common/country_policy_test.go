@@ -0,0 +1,142 @@
+package common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCountryPolicy_Resolve(t *testing.T) {
+	policy := &CountryPolicy{
+		Allowed:      append(EEA, "GB", "XK"),
+		UserAssigned: []Country{Kosovo},
+		Aliases:      Aliases,
+		Test:         []string{"ZZ"},
+	}
+
+	cases := []struct {
+		code     string
+		expected string
+		err      error
+	}{
+		{code: "NL", expected: "NL"},
+		{code: "no", expected: "NO"},
+		{code: "XK", expected: "XK"},
+		{code: "UK", expected: "GB"},
+		{code: "EL", expected: "GR"},
+		{code: "ZZ", expected: "ZZ"},
+		{code: "US", err: ErrCountryNotAllowed},
+		{code: "XY", err: ErrUnknownCountry},
+		{code: "", err: ErrUnknownCountry},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.code, func(t *testing.T) {
+			country, err := policy.Resolve(tc.code)
+
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				assert.ErrorIs(t, err, ErrInvalidCountryCode)
+				assert.True(t, country.IsZero())
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, country.Alpha2)
+		})
+	}
+}
+
+func TestCountryPolicy_ZeroValue(t *testing.T) {
+	var policy CountryPolicy
+
+	assert.Nil(t, policy.Check("US"))
+	assert.ErrorIs(t, policy.Check("XK"), ErrUnknownCountry)
+	assert.ErrorIs(t, policy.Check("UK"), ErrUnknownCountry)
+	assert.False(t, policy.IsTest("ZZ"))
+}
+
+func TestCountryPolicy_CheckCallingCode(t *testing.T) {
+	policy := &CountryPolicy{
+		Allowed:      []string{"CA", "DE", "XK"},
+		UserAssigned: []Country{Kosovo},
+		Test:         []string{"999"},
+	}
+
+	assert.Nil(t, policy.CheckCallingCode("+49"))
+	assert.Nil(t, policy.CheckCallingCode("49"))
+	assert.Nil(t, policy.CheckCallingCode("+1"), "shared by Canada")
+	assert.Nil(t, policy.CheckCallingCode("+383"))
+	assert.Nil(t, policy.CheckCallingCode("999"))
+	assert.ErrorIs(t, policy.CheckCallingCode("+31"), ErrCountryNotAllowed)
+	assert.ErrorIs(t, policy.CheckCallingCode("+7"), ErrCountryNotAllowed)
+	assert.ErrorIs(t, policy.CheckCallingCode("+990"), ErrUnknownCountry)
+}
+
+func TestCountryPolicy_ResolveCallingCode(t *testing.T) {
+	cases := []struct {
+		name     string
+		policy   CountryPolicy
+		code     string
+		expected Country
+	}{
+		{"primary country", CountryPolicy{}, "+39", CountryOf("IT")},
+		{"allowed primary country", CountryPolicy{Allowed: EU}, "39", CountryOf("IT")},
+		{"first allowed country", CountryPolicy{Allowed: []string{"VA"}}, "+39", CountryOf("VA")},
+		{"user-assigned country", CountryPolicy{UserAssigned: []Country{Kosovo}}, "+383", Kosovo},
+		{"test code", CountryPolicy{Test: []string{"999"}}, "+999", Country{CallingCode: "+999"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			country, err := tc.policy.ResolveCallingCode(tc.code)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, country)
+		})
+	}
+}
+
+func TestAccepting(t *testing.T) {
+	assert.Nil(t, Accepting("NL", Country{}))
+
+	for _, tc := range []struct {
+		code    string
+		country Country
+	}{
+		{"XK", Kosovo},
+		{"uk", CountryOf("GB")},
+		{"ZZ", Country{Alpha2: "ZZ"}},
+	} {
+		policy := Accepting(tc.code, tc.country)
+
+		country, err := policy.Resolve(tc.code)
+		assert.Nil(t, err, tc.code)
+		assert.Equal(t, tc.country, country, tc.code)
+		assert.ErrorIs(t, policy.Check("NL"), ErrCountryNotAllowed, tc.code)
+	}
+
+	for _, tc := range []struct {
+		code    string
+		country Country
+	}{
+		{"+383", Kosovo},
+		{"+39", CountryOf("VA")},
+		{"999", Country{CallingCode: "+999"}},
+	} {
+		policy := Accepting(tc.code, tc.country)
+
+		country, err := policy.ResolveCallingCode(tc.code)
+		assert.Nil(t, err, tc.code)
+		assert.Equal(t, tc.country, country, tc.code)
+	}
+}
+
+func TestRegions(t *testing.T) {
+	assert.Len(t, EU, 27)
+	assert.Len(t, EEA, 30)
+	assert.Subset(t, EEA, EU)
+
+	for _, code := range EEA {
+		assert.True(t, IsKnownCountryCode(code), code)
+	}
+}
@@ -0,0 +1,136 @@
+package common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var germany = Country{Alpha2: "DE", Alpha3: "DEU", Numeric: "276", Name: "Germany", CallingCode: "+49"}
+
+func TestLookupCountry(t *testing.T) {
+	cases := []struct {
+		code     string
+		expected Country
+		found    bool
+	}{
+		{"DE", germany, true},
+		{"de", germany, true},
+		{"DEU", germany, true},
+		{"276", germany, true},
+		{"AG", Country{Alpha2: "AG", Alpha3: "ATG", Numeric: "028", Name: "Antigua and Barbuda", CallingCode: "+1"}, true},
+		{"AQ", Country{Alpha2: "AQ", Alpha3: "ATA", Numeric: "010", Name: "Antarctica"}, true},
+		{"ZZ", Country{}, false},
+		{"", Country{}, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.code, func(t *testing.T) {
+			country, found := LookupCountry(tc.code)
+
+			assert.Equal(t, tc.found, found)
+			assert.Equal(t, tc.expected, country)
+		})
+	}
+}
+
+func TestCountryOf(t *testing.T) {
+	cases := []struct {
+		code     string
+		expected Country
+	}{
+		{"DE", germany},
+		{"+49", germany},
+		{"DEU", Country{}},
+		{"+1", Country{Alpha2: "US", Alpha3: "USA", Numeric: "840", Name: "United States", CallingCode: "+1"}},
+		{"+39", Country{Alpha2: "IT", Alpha3: "ITA", Numeric: "380", Name: "Italy", CallingCode: "+39"}},
+		{"+999", Country{}},
+		{"ZZ", Country{}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.code, func(t *testing.T) {
+			assert.Equal(t, tc.expected, CountryOf(tc.code))
+		})
+	}
+}
+
+func TestCountryOf_SharedCallingCodes(t *testing.T) {
+	for callingCode, expected := range map[string]string{
+		"+7": "RU", "+39": "IT", "+44": "GB", "+47": "NO", "+61": "AU", "+358": "FI",
+	} {
+		country := CountryOf(callingCode)
+
+		assert.Equal(t, expected, country.Alpha2, callingCode)
+		assert.Equal(t, callingCode, country.CallingCode, callingCode)
+	}
+}
+
+func TestCountry(t *testing.T) {
+	assert.Equal(t, "DE", germany.String())
+	assert.False(t, germany.IsZero())
+	assert.True(t, Country{}.IsZero())
+}
+
+func TestIsValidCountryCode(t *testing.T) {
+	assert.True(t, IsValidCountryCode("DE"))
+	assert.True(t, IsValidCountryCode("deu"))
+	assert.False(t, IsValidCountryCode("276"))
+	assert.False(t, IsValidCountryCode("ZZ"))
+	assert.False(t, IsValidCountryCode(""))
+}
+
+func TestIsKnownCountryCode(t *testing.T) {
+	assert.True(t, IsKnownCountryCode("DE"))
+	assert.True(t, IsKnownCountryCode([]byte("de")))
+	assert.False(t, IsKnownCountryCode("DEU"))
+	assert.False(t, IsKnownCountryCode("ZZ"))
+	assert.False(t, IsKnownCountryCode("D1"))
+
+	allocs := testing.AllocsPerRun(100, func() {
+		IsKnownCountryCode([]byte("nl"))
+	})
+	assert.Zero(t, allocs)
+}
+
+func TestCountries_AreWellFormed(t *testing.T) {
+	countries := Countries()
+	assert.Len(t, countries, 249)
+
+	for i, country := range countries {
+		assert.Regexp(t, `^[A-Z]{2}$`, country.Alpha2)
+		assert.Regexp(t, `^[A-Z]{3}$`, country.Alpha3, country.Alpha2)
+		assert.Regexp(t, `^[0-9]{3}$`, country.Numeric, country.Alpha2)
+		assert.NotEmpty(t, country.Name, country.Alpha2)
+		if i > 0 {
+			assert.Less(t, countries[i-1].Alpha2, country.Alpha2)
+		}
+	}
+}
+
+func TestParseCountries_Errors(t *testing.T) {
+	cases := []struct {
+		name string
+		data string
+	}{
+		{"missing fields", "DE\tDEU\t276\t+49\n"},
+		{"invalid alpha-2 code", "D1\tDEU\t276\t+49\tGermany\n"},
+		{"duplicate alpha-2 code", "DE\tDEU\t276\t+49\tGermany\nDE\tDEX\t277\t+49\tGermany\n"},
+		{"duplicate alpha-3 code", "DE\tDEU\t276\t+49\tGermany\nDX\tDEU\t277\t+49\tGermany\n"},
+		{"shared calling code without primary", "IT\tITA\t380\t+39\tItaly\nVA\tVAT\t336\t+39\tHoly See\n"},
+		{"shared calling code with two primaries", "IT\tITA\t380\t+39!\tItaly\nVA\tVAT\t336\t+39!\tHoly See\n"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseCountries(tc.data)
+
+			assert.NotNil(t, err)
+		})
+	}
+}
+
+func BenchmarkIsKnownCountryCode(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		IsKnownCountryCode("NL")
+	}
+}
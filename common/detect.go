@@ -0,0 +1,37 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Rejection explains why an input was not recognized as an identifier of a given format
+type Rejection struct {
+	Format Format
+	Err    error
+}
+
+// NoMatchError is returned when an input doesn't match any of the formats an identifier of a given kind can have
+type NoMatchError struct {
+	Kind       Kind
+	Input      string
+	Rejections []Rejection
+}
+
+func (e *NoMatchError) Error() string {
+	if len(e.Rejections) == 0 {
+		return fmt.Sprintf("unrecognized %v ID: %v (%v, import mobilityid/formats or the package of each format)", e.Kind, e.Input, ErrNoFormats)
+	}
+
+	reasons := make([]string, 0, len(e.Rejections))
+	for _, r := range e.Rejections {
+		reasons = append(reasons, fmt.Sprintf("%v: %v", r.Format, r.Err))
+	}
+
+	return fmt.Sprintf("unrecognized %v ID: %v (%s)", e.Kind, e.Input, strings.Join(reasons, "; "))
+}
+
+// Is reports whether target is ErrNoFormats, and no format was tried
+func (e *NoMatchError) Is(target error) bool {
+	return target == ErrNoFormats && len(e.Rejections) == 0
+}
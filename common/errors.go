@@ -0,0 +1,220 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Sentinel errors, to be matched with errors.Is against the errors returned by constructors, parsers and validators.
+// Errors about a field match both the sentinel of the field (e.g. ErrInvalidCountryCode) and the one of the reason
+// why it is invalid (e.g. ErrWrongLength).
+var (
+	ErrInvalidFormat        = errors.New("invalid format")
+	ErrInvalidCountryCode   = errors.New("invalid country code")
+	ErrInvalidPartyCode     = errors.New("invalid party code")
+	ErrInvalidOperatorCode  = errors.New("invalid operator code")
+	ErrInvalidInstance      = errors.New("invalid instance value")
+	ErrInvalidPowerOutletId = errors.New("invalid power outlet ID")
+	ErrInvalidPoolId        = errors.New("invalid charging pool ID")
+	ErrInvalidStationId     = errors.New("invalid charging station ID")
+	ErrInvalidCheckDigit    = errors.New("invalid check digit")
+
+	ErrRequired          = errors.New("value is required")
+	ErrWrongLength       = errors.New("wrong length")
+	ErrInvalidCharacter  = errors.New("invalid character")
+	ErrUnknownCountry    = errors.New("unknown country")
+	ErrCountryNotAllowed = errors.New("country not allowed")
+
+	// ErrNoFormats is matched by the *NoMatchError returned when parsing an identifier of unknown format, if no format
+	// of its kind is registered, i.e. if no format package is imported (see package mobilityid/formats).
+	ErrNoFormats = errors.New("no format registered")
+)
+
+// Field is the name of a field of an identifier
+type Field string
+
+const (
+	FieldCountryCode   Field = "countryCode"
+	FieldPartyCode     Field = "partyCode"
+	FieldOperatorCode  Field = "operatorCode"
+	FieldInstance      Field = "instanceValue"
+	FieldPowerOutletId Field = "powerOutletId"
+	FieldPoolId        Field = "poolId"
+	FieldStationId     Field = "stationId"
+	FieldCheckDigit    Field = "checkDigit"
+)
+
+var fieldErrors = map[Field]error{
+	FieldCountryCode:   ErrInvalidCountryCode,
+	FieldPartyCode:     ErrInvalidPartyCode,
+	FieldOperatorCode:  ErrInvalidOperatorCode,
+	FieldInstance:      ErrInvalidInstance,
+	FieldPowerOutletId: ErrInvalidPowerOutletId,
+	FieldPoolId:        ErrInvalidPoolId,
+	FieldStationId:     ErrInvalidStationId,
+	FieldCheckDigit:    ErrInvalidCheckDigit,
+}
+
+// FieldError reports an invalid field value
+type FieldError struct {
+	Field Field
+	Value string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s '%s' is not valid: %v", e.Field, e.Value, e.Err)
+}
+
+// Unwrap returns the reason why the field is invalid
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the sentinel error of the field (e.g. ErrInvalidCountryCode for FieldCountryCode)
+func (e *FieldError) Is(target error) bool {
+	return target != nil && fieldErrors[e.Field] == target
+}
+
+// LengthError reports a value whose length is not within the expected range
+type LengthError struct {
+	Min, Max, Actual int
+}
+
+func (e *LengthError) Error() string {
+	if e.Min == e.Max {
+		return fmt.Sprintf("length must be exactly %d, got %d", e.Min, e.Actual)
+	}
+
+	return fmt.Sprintf("length must be between %d and %d, got %d", e.Min, e.Max, e.Actual)
+}
+
+// Is reports whether target is ErrWrongLength
+func (e *LengthError) Is(target error) bool {
+	return target == ErrWrongLength
+}
+
+// CheckDigitError reports a check digit not matching the one computed from the other fields
+type CheckDigitError struct {
+	Expected, Actual rune
+}
+
+func (e *CheckDigitError) Error() string {
+	return fmt.Sprintf("check digit '%c' is invalid, expected '%c'", e.Actual, e.Expected)
+}
+
+// Is reports whether target is ErrInvalidCheckDigit
+func (e *CheckDigitError) Is(target error) bool {
+	return target == ErrInvalidCheckDigit
+}
+
+// FormatError reports an input not matching the syntax of an identifier format
+type FormatError struct {
+	Kind   Kind
+	Format Format
+	Input  string
+	Hint   string
+	// Fields lists the invalid values of the input, when its separators tell where each of them is, e.g. an operator
+	// code of 4 characters in "NL*TNMX*E03*0"; it is empty if they don't, or if the values are valid but not their
+	// layout, e.g. a missing separator.
+	Fields ValidationErrors
+}
+
+func (e *FormatError) Error() string {
+	result := fmt.Sprintf("not %s %v %v ID: %v", e.Format.article(), e.Format, e.Kind, e.Input)
+	if e.Hint != "" {
+		result = fmt.Sprintf("%s (%s)", result, e.Hint)
+	}
+	if len(e.Fields) > 0 {
+		result = fmt.Sprintf("%s: %v", result, e.Fields)
+	}
+
+	return result
+}
+
+// Is reports whether target is ErrInvalidFormat, or is matched by any of the errors of the invalid fields
+func (e *FormatError) Is(target error) bool {
+	return target == ErrInvalidFormat || e.Fields.Is(target)
+}
+
+// ValidationErrors reports every invalid field of an identifier, so that they can all be shown at once
+type ValidationErrors []*FieldError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap returns the errors of the individual fields
+func (e ValidationErrors) Unwrap() []error {
+	result := make([]error, 0, len(e))
+	for _, err := range e {
+		result = append(result, err)
+	}
+
+	return result
+}
+
+// Is reports whether any of the errors of the individual fields matches target
+func (e ValidationErrors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Fields returns the names of the invalid fields
+func (e ValidationErrors) Fields() []Field {
+	result := make([]Field, 0, len(e))
+	for _, err := range e {
+		result = append(result, err.Field)
+	}
+
+	return result
+}
+
+// Add appends err to the list, if not nil: a *FieldError is appended as is, the errors of a ValidationErrors are
+// appended one by one, and any other error is reported as an error of field.
+func (e *ValidationErrors) Add(field Field, err error) {
+	var fieldErr *FieldError
+	var validationErrs ValidationErrors
+
+	switch {
+	case err == nil:
+		return
+	case errors.As(err, &validationErrs):
+		*e = append(*e, validationErrs...)
+	case errors.As(err, &fieldErr):
+		*e = append(*e, fieldErr)
+	default:
+		*e = append(*e, &FieldError{Field: field, Err: err})
+	}
+}
+
+// Err returns nil if the list is empty, the list itself otherwise
+func (e ValidationErrors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+
+	return e
+}
+
+// FirstError returns the first of errs which is not nil, if any
+func FirstError(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFieldError(t *testing.T) {
+	t.Run("matches the sentinel of its field and the one of its reason", func(t *testing.T) {
+		err := fmt.Errorf("wrapped: %w", &FieldError{Field: FieldPartyCode, Value: "TNMA", Err: &LengthError{Min: 3, Max: 3, Actual: 4}})
+
+		assert.True(t, errors.Is(err, ErrInvalidPartyCode))
+		assert.True(t, errors.Is(err, ErrWrongLength))
+		assert.False(t, errors.Is(err, ErrInvalidCountryCode))
+
+		var fieldErr *FieldError
+		assert.True(t, errors.As(err, &fieldErr))
+		assert.Equal(t, FieldPartyCode, fieldErr.Field)
+		assert.Equal(t, "TNMA", fieldErr.Value)
+		assert.Equal(t, "partyCode 'TNMA' is not valid: length must be exactly 3, got 4", fieldErr.Error())
+	})
+}
+
+func TestCheckDigitError(t *testing.T) {
+	t.Run("carries the expected and actual check digits", func(t *testing.T) {
+		err := &FieldError{Field: FieldCheckDigit, Value: "A", Err: &CheckDigitError{Expected: 'X', Actual: 'A'}}
+
+		assert.True(t, errors.Is(err, ErrInvalidCheckDigit))
+
+		var checkDigitErr *CheckDigitError
+		assert.True(t, errors.As(err, &checkDigitErr))
+		assert.Equal(t, 'X', checkDigitErr.Expected)
+		assert.Equal(t, 'A', checkDigitErr.Actual)
+	})
+}
+
+func TestFormatError(t *testing.T) {
+	t.Run("matches ErrInvalidFormat", func(t *testing.T) {
+		err := &FormatError{Kind: KindContract, Format: FormatDin, Input: "XYZ"}
+
+		assert.True(t, errors.Is(err, ErrInvalidFormat))
+		assert.False(t, errors.Is(err, ErrWrongLength))
+		assert.Equal(t, "not a DIN contract ID: XYZ", err.Error())
+	})
+
+	t.Run("matches the errors of its invalid fields", func(t *testing.T) {
+		err := &FormatError{
+			Kind:   KindContract,
+			Format: FormatDin,
+			Input:  "NL-TNMX-012204",
+			Fields: ValidationErrors{{Field: FieldPartyCode, Value: "TNMX", Err: &LengthError{Min: 3, Max: 3, Actual: 4}}},
+		}
+
+		assert.True(t, errors.Is(err, ErrInvalidFormat))
+		assert.True(t, errors.Is(err, ErrInvalidPartyCode))
+		assert.True(t, errors.Is(err, ErrWrongLength))
+		assert.False(t, errors.Is(err, ErrInvalidInstance))
+		assert.Equal(
+			t,
+			"not a DIN contract ID: NL-TNMX-012204: partyCode 'TNMX' is not valid: length must be exactly 3, got 4",
+			err.Error(),
+		)
+	})
+}
+
+func TestValidateLength(t *testing.T) {
+	assert.Nil(t, ValidateLength(FieldInstance, "123", 1, 3))
+	assert.True(t, errors.Is(ValidateLength(FieldInstance, "", 1, 3), ErrRequired))
+	assert.True(t, errors.Is(ValidateLength(FieldInstance, "1234", 1, 3), ErrWrongLength))
+}
+
+func TestValidateCountryCode(t *testing.T) {
+	assert.Nil(t, ValidateCountryCode("NL"))
+	assert.True(t, errors.Is(ValidateCountryCode("ZZ"), ErrUnknownCountry))
+	assert.True(t, errors.Is(ValidateCountryCode("NLD"), ErrWrongLength))
+}
+
+func TestValidationErrors(t *testing.T) {
+	t.Run("collects the errors of every field", func(t *testing.T) {
+		var errs ValidationErrors
+		errs.Add(FieldCountryCode, ValidateCountryCode("ZZ"))
+		errs.Add(FieldPartyCode, nil)
+		errs.Add(FieldInstance, ValidateLength(FieldInstance, "", 1, 3))
+
+		err := errs.Err()
+
+		assert.Equal(t, []Field{FieldCountryCode, FieldInstance}, errs.Fields())
+		assert.True(t, errors.Is(err, ErrInvalidCountryCode))
+		assert.True(t, errors.Is(err, ErrInvalidInstance))
+		assert.True(t, errors.Is(err, ErrRequired))
+		assert.False(t, errors.Is(err, ErrInvalidPartyCode))
+	})
+
+	t.Run("returns no error if empty", func(t *testing.T) {
+		var errs ValidationErrors
+
+		assert.Nil(t, errs.Err())
+	})
+}
@@ -0,0 +1,71 @@
+package common
+
+// Kind is the kind of entity identified by an Identifier
+type Kind int
+
+const (
+	KindContract Kind = iota + 1
+	KindEvse
+	KindParty
+	KindPool
+	KindStation
+)
+
+// String returns the name of the kind
+func (k Kind) String() string {
+	switch k {
+	case KindContract:
+		return "contract"
+	case KindEvse:
+		return "EVSE"
+	case KindParty:
+		return "party"
+	case KindPool:
+		return "charging pool"
+	case KindStation:
+		return "charging station"
+	default:
+		return "unknown"
+	}
+}
+
+// Format is the standard an Identifier is expressed in
+type Format int
+
+const (
+	FormatIso Format = iota + 1
+	FormatEmi3
+	FormatDin
+)
+
+// String returns the name of the format
+func (f Format) String() string {
+	switch f {
+	case FormatIso:
+		return "ISO"
+	case FormatEmi3:
+		return "EMI3"
+	case FormatDin:
+		return "DIN"
+	default:
+		return "unknown"
+	}
+}
+
+func (f Format) article() string {
+	if f == FormatDin {
+		return "a"
+	}
+
+	return "an"
+}
+
+// Identifier provides functions shared by all mobility IDs, regardless of their kind and format
+type Identifier interface {
+	Kind() Kind
+	Format() Format
+	Country() Country
+	PartyId() string
+	String() string
+	CompactString() string
+}
@@ -0,0 +1,22 @@
+package common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestKind_String(t *testing.T) {
+	assert.Equal(t, "contract", KindContract.String())
+	assert.Equal(t, "EVSE", KindEvse.String())
+	assert.Equal(t, "party", KindParty.String())
+	assert.Equal(t, "charging pool", KindPool.String())
+	assert.Equal(t, "charging station", KindStation.String())
+	assert.Equal(t, "unknown", Kind(0).String())
+}
+
+func TestFormat_String(t *testing.T) {
+	assert.Equal(t, "ISO", FormatIso.String())
+	assert.Equal(t, "EMI3", FormatEmi3.String())
+	assert.Equal(t, "DIN", FormatDin.String())
+	assert.Equal(t, "unknown", Format(0).String())
+}
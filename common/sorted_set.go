@@ -0,0 +1,110 @@
+package common
+
+import (
+	"sort"
+)
+
+// SortedSet is a set of items kept sorted by a comparison function, which must define a total order: two items are
+// the same if it returns 0. Lookups and range queries take logarithmic time, adding or removing an item linear time.
+//
+// The zero value is an empty set without comparison function, to which items can't be added: sets must be created
+// with NewSortedSet.
+type SortedSet[T any] struct {
+	compare func(a, b T) int
+	items   []T
+}
+
+// NewSortedSet returns a set of items, sorted by compare, in O(n log n) time; of items which are the same, only one is
+// kept.
+func NewSortedSet[T any](compare func(a, b T) int, items ...T) SortedSet[T] {
+	sorted := append([]T(nil), items...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return compare(sorted[i], sorted[j]) < 0
+	})
+
+	// sorted[:n] holds the distinct items seen so far, the same items being adjacent
+	n := 0
+	for _, item := range sorted {
+		if n == 0 || compare(sorted[n-1], item) != 0 {
+			sorted[n] = item
+			n++
+		}
+	}
+
+	return SortedSet[T]{compare: compare, items: sorted[:n]}
+}
+
+// search returns the index of item, or the one it would be inserted at, and whether it was found
+func (s *SortedSet[T]) search(item T) (int, bool) {
+	i := sort.Search(len(s.items), func(i int) bool {
+		return s.compare(s.items[i], item) >= 0
+	})
+
+	return i, i < len(s.items) && s.compare(s.items[i], item) == 0
+}
+
+// Add adds item to the set, returning false if it was already there
+func (s *SortedSet[T]) Add(item T) bool {
+	if s.compare == nil {
+		panic("common: SortedSet has no comparison function, it must be created with NewSortedSet")
+	}
+
+	i, found := s.search(item)
+	if found {
+		return false
+	}
+
+	var zero T
+	s.items = append(s.items, zero)
+	copy(s.items[i+1:], s.items[i:])
+	s.items[i] = item
+
+	return true
+}
+
+// Remove removes item from the set, returning false if it wasn't there
+func (s *SortedSet[T]) Remove(item T) bool {
+	i, found := s.search(item)
+	if !found {
+		return false
+	}
+
+	s.items = append(s.items[:i], s.items[i+1:]...)
+
+	return true
+}
+
+// Contains returns true if item is in the set
+func (s *SortedSet[T]) Contains(item T) bool {
+	_, found := s.search(item)
+
+	return found
+}
+
+// Len returns the number of items in the set
+func (s *SortedSet[T]) Len() int {
+	return len(s.items)
+}
+
+// Items returns a copy of the items of the set, in order
+func (s *SortedSet[T]) Items() []T {
+	return append([]T(nil), s.items...)
+}
+
+// Between returns the items within bounds, in order. lower (upper) compares an item with the lower (upper) bound,
+// returning a negative number if it precedes it, 0 if it matches it and a positive one if it follows it; both must be
+// consistent with the order of the set.
+func (s *SortedSet[T]) Between(lower, upper func(item T) int) []T {
+	from := sort.Search(len(s.items), func(i int) bool {
+		return lower(s.items[i]) >= 0
+	})
+	to := sort.Search(len(s.items), func(i int) bool {
+		return upper(s.items[i]) > 0
+	})
+
+	if from >= to {
+		return nil
+	}
+
+	return append([]T(nil), s.items[from:to]...)
+}
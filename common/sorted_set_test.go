@@ -0,0 +1,57 @@
+package common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestSortedSet(t *testing.T) {
+	s := NewSortedSet(strings.Compare, "c", "a", "b", "a")
+
+	assert.Equal(t, 3, s.Len())
+	assert.Equal(t, []string{"a", "b", "c"}, s.Items())
+	assert.True(t, s.Contains("b"))
+	assert.False(t, s.Contains("d"))
+
+	assert.True(t, s.Add("d"))
+	assert.False(t, s.Add("d"))
+	assert.True(t, s.Remove("a"))
+	assert.False(t, s.Remove("a"))
+	assert.Equal(t, []string{"b", "c", "d"}, s.Items())
+}
+
+func TestNewSortedSet_DropsDuplicates(t *testing.T) {
+	s := NewSortedSet(func(a, b string) int {
+		return strings.Compare(strings.ToUpper(a), strings.ToUpper(b))
+	}, "b", "A", "a", "B", "c", "a")
+
+	assert.Equal(t, 3, s.Len())
+	assert.True(t, s.Contains("a"))
+	assert.True(t, s.Contains("B"))
+	assert.Equal(t, "c", s.Items()[2])
+}
+
+func TestSortedSet_ZeroValue(t *testing.T) {
+	var s SortedSet[string]
+
+	assert.Zero(t, s.Len())
+	assert.False(t, s.Contains("a"))
+	assert.False(t, s.Remove("a"))
+	assert.PanicsWithValue(t, "common: SortedSet has no comparison function, it must be created with NewSortedSet", func() {
+		s.Add("a")
+	})
+}
+
+func TestSortedSet_Between(t *testing.T) {
+	s := NewSortedSet(strings.Compare, "a1", "b1", "b2", "b3", "c1")
+	bound := func(b string) func(string) int {
+		return func(item string) int {
+			return strings.Compare(item, b)
+		}
+	}
+
+	assert.Equal(t, []string{"b1", "b2"}, s.Between(bound("b1"), bound("b2")))
+	assert.Equal(t, []string{"b2", "b3", "c1"}, s.Between(bound("b15"), bound("z")))
+	assert.Nil(t, s.Between(bound("b4"), bound("b9")))
+}
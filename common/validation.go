@@ -0,0 +1,56 @@
+package common
+
+import "unicode/utf8"
+
+// ValidateLength returns a *FieldError if value is empty or its length is not between min and max
+func ValidateLength(field Field, value string, min, max int) error {
+	length := utf8.RuneCountInString(value)
+	if length == 0 {
+		return &FieldError{Field: field, Value: value, Err: ErrRequired}
+	}
+
+	if length < min || length > max {
+		return &FieldError{Field: field, Value: value, Err: &LengthError{Min: min, Max: max, Actual: length}}
+	}
+
+	return nil
+}
+
+// ValidateCharacters returns a *FieldError if any of the runes of value doesn't satisfy isValid
+func ValidateCharacters(field Field, value string, isValid func(rune) bool) error {
+	for _, r := range value {
+		if !isValid(r) {
+			return &FieldError{Field: field, Value: value, Err: ErrInvalidCharacter}
+		}
+	}
+
+	return nil
+}
+
+// ValidateCountryCode returns a *FieldError if code is not the alpha-2 code of a known country
+func ValidateCountryCode(code string) error {
+	if err := ValidateLength(FieldCountryCode, code, 2, 2); err != nil {
+		return err
+	}
+
+	if !IsValidCountryCode(code) {
+		return &FieldError{Field: FieldCountryCode, Value: code, Err: ErrUnknownCountry}
+	}
+
+	return nil
+}
+
+// IsAlphanumeric returns true if r is an ASCII letter (of any case) or a decimal digit
+func IsAlphanumeric(r rune) bool {
+	return r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || IsDigit(r)
+}
+
+// IsUpperAlphanumeric returns true if r is an upper case ASCII letter or a decimal digit
+func IsUpperAlphanumeric(r rune) bool {
+	return r >= 'A' && r <= 'Z' || IsDigit(r)
+}
+
+// IsDigit returns true if r is an ASCII decimal digit
+func IsDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
@@ -0,0 +1,101 @@
+package contractid
+
+import (
+	c "mobilityid/common"
+)
+
+// Compare defines a total order of contract IDs, whatever their format: by country code, party code and instance
+// value in the form of CanonicalKey (e.g. "C00122045" for the EMI3 instance value "00122045"), ignoring case, so that
+// equivalent contract IDs are adjacent, then by check digit (IDs without one first) and finally by format. It returns
+// -1, 0 or +1, so it can be passed to slices.SortFunc or slices.BinarySearchFunc, e.g.
+//
+//	slices.SortFunc(ids, contractid.Compare[iso.ContractId])
+func Compare[T Reader](a, b T) int {
+	if r := compareInstance(a, b.CountryCode(), b.PartyCode(), keyOf(b)); r != 0 {
+		return r
+	}
+
+	if r := compareRunes(upper(a.CheckDigit()), upper(b.CheckDigit())); r != 0 {
+		return r
+	}
+
+	switch {
+	case a.Format() < b.Format():
+		return -1
+	case a.Format() > b.Format():
+		return 1
+	default:
+		return 0
+	}
+}
+
+// compareParty compares the party of id with the one identified by countryCode and partyCode, ignoring case
+func compareParty[T Reader](id T, countryCode, partyCode string) int {
+	if r := c.CompareFold(id.CountryCode(), countryCode, ""); r != 0 {
+		return r
+	}
+
+	return c.CompareFold(id.PartyCode(), partyCode, "")
+}
+
+// compareInstance compares the party and canonical instance value of id with the given ones, ignoring case
+func compareInstance[T Reader](id T, countryCode, partyCode string, instance instanceKey) int {
+	if r := compareParty(id, countryCode, partyCode); r != 0 {
+		return r
+	}
+
+	return compareKeys(keyOf(id), instance)
+}
+
+func compareRunes(a, b rune) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func upper(r rune) rune {
+	if r >= 'a' && r <= 'z' {
+		return r - ('a' - 'A')
+	}
+
+	return r
+}
+
+// Set is a set of contract IDs, sorted by Compare; it must be created with NewSet.
+type Set[T Reader] struct {
+	c.SortedSet[T]
+}
+
+// NewSet returns a set of ids
+func NewSet[T Reader](ids ...T) *Set[T] {
+	return &Set[T]{c.NewSortedSet(Compare[T], ids...)}
+}
+
+// Party returns the IDs of the party identified by countryCode and partyCode, in order
+func (s *Set[T]) Party(countryCode, partyCode string) []T {
+	bound := func(id T) int {
+		return compareParty(id, countryCode, partyCode)
+	}
+
+	return s.Between(bound, bound)
+}
+
+// Range returns the IDs of the party identified by countryCode and partyCode whose instance value, in the form of
+// CanonicalKey, is between from and to, both included, in order; e.g. the contract IDs of NL-TNM from instance
+// "C00000000" to "C00999999", including the EMI3 contract ID "NL-TNM-C00122045-K" and the DIN contract ID
+// "NL-TNM-012204-5", whose canonical instance value is "C00122045".
+func (s *Set[T]) Range(countryCode, partyCode, from, to string) []T {
+	return s.Between(
+		func(id T) int {
+			return compareInstance(id, countryCode, partyCode, instanceKey{value: from})
+		},
+		func(id T) int {
+			return compareInstance(id, countryCode, partyCode, instanceKey{value: to})
+		},
+	)
+}
@@ -0,0 +1,94 @@
+package contractid_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"mobilityid/contractid"
+	"mobilityid/contractid/din"
+	"mobilityid/contractid/emi3"
+	"mobilityid/contractid/iso"
+	"sort"
+	"testing"
+)
+
+func mustParseEmi3(input string) emi3.ContractId {
+	id, err := emi3.Parse(input)
+	if err != nil {
+		panic(err)
+	}
+
+	return id
+}
+
+func TestCompare(t *testing.T) {
+	withCheckDigit := mustParseEmi3("NL-TNM-C00122045-K")
+	withoutCheckDigit := mustParseEmi3("NL-TNM-C00122045")
+
+	assert.Equal(t, 0, contractid.Compare(withCheckDigit, mustParseEmi3("nltnmc00122045k")))
+	assert.Equal(t, -1, contractid.Compare(withoutCheckDigit, withCheckDigit))
+	assert.Equal(t, -1, contractid.Compare(mustParseEmi3("DE-TNM-C00122045"), withoutCheckDigit))
+	assert.Equal(t, -1, contractid.Compare(mustParseEmi3("NL-ABC-C99999999"), withoutCheckDigit))
+	assert.Equal(t, 1, contractid.Compare(mustParseEmi3("NL-TNM-C00122046"), withCheckDigit))
+
+	t.Run("orders IDs of different formats", func(t *testing.T) {
+		isoId, _ := iso.Parse("NL-TNM-C00122045-K")
+		dinId, _ := din.Parse("NL-TNM-012204-5")
+
+		ids := []contractid.Reader{isoId, withCheckDigit, dinId}
+		sort.Slice(ids, func(i, j int) bool {
+			return contractid.Compare(ids[i], ids[j]) < 0
+		})
+
+		// equivalent IDs are adjacent, ordered by check digit, then by format
+		assert.Equal(t, []contractid.Reader{dinId, isoId, withCheckDigit}, ids)
+		assert.Equal(t, -1, contractid.Compare[contractid.Reader](isoId, withCheckDigit))
+	})
+
+	t.Run("orders instance values in the form of CanonicalKey", func(t *testing.T) {
+		isoId, _ := iso.Parse("NL-TNM-001234567")
+		nextIsoId, _ := iso.Parse("NL-TNM-C00122046")
+
+		ids := []contractid.Reader{nextIsoId, withCheckDigit, isoId}
+		sort.Slice(ids, func(i, j int) bool {
+			return contractid.Compare(ids[i], ids[j]) < 0
+		})
+
+		assert.Equal(t, []contractid.Reader{isoId, withCheckDigit, nextIsoId}, ids)
+	})
+}
+
+func TestSet(t *testing.T) {
+	s := contractid.NewSet(
+		mustParseEmi3("NL-TNM-C00000003"),
+		mustParseEmi3("DE-TNM-C00000001"),
+		mustParseEmi3("NL-TNM-C00000001"),
+		mustParseEmi3("NL-TNM-C00000002"),
+		mustParseEmi3("NL-ABC-C00000002"),
+		mustParseEmi3("nl-tnm-c00000002"),
+	)
+
+	assert.Equal(t, 5, s.Len())
+	assert.True(t, s.Contains(mustParseEmi3("NL-TNM-C00000003")))
+
+	assert.Equal(t, []emi3.ContractId{
+		mustParseEmi3("NL-TNM-C00000001"),
+		mustParseEmi3("NL-TNM-C00000002"),
+		mustParseEmi3("NL-TNM-C00000003"),
+	}, s.Party("NL", "TNM"))
+
+	assert.Equal(t, []emi3.ContractId{
+		mustParseEmi3("NL-TNM-C00000002"),
+		mustParseEmi3("NL-TNM-C00000003"),
+	}, s.Range("nl", "tnm", "c00000002", "C00000009"))
+
+	assert.Empty(t, s.Range("NL", "TNM", "C00000004", "C00000009"))
+}
+
+func TestSet_MixedFormats(t *testing.T) {
+	emi3Id := mustParseEmi3("NL-TNM-C00122045-K")
+	dinId, _ := din.Parse("NL-TNM-012204-5")
+	isoId, _ := iso.Parse("NL-TNM-C00122046")
+	s := contractid.NewSet[contractid.Reader](isoId, emi3Id, dinId)
+
+	assert.Equal(t, []contractid.Reader{dinId, emi3Id}, s.Range("NL", "TNM", "C00122000", "C00122045"))
+	assert.Equal(t, []contractid.Reader{dinId, emi3Id, isoId}, s.Range("NL", "TNM", "C00122000", "C00122999"))
+}
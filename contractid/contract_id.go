@@ -1,9 +1,8 @@
 package contractid
 
 import (
-	"fmt"
-	v "github.com/go-ozzo/ozzo-validation"
 	c "mobilityid/common"
+	"mobilityid/grammar"
 	"strings"
 )
 
@@ -20,21 +19,34 @@ type Reader interface {
 	PartyCode() string
 	InstanceValue() string
 	CheckDigit() rune
+	HasCheckDigit() bool
 	PartyId() string
 	CompactPartyId() string
+	IsZero() bool
+	Validate() error
 	Stringer
+	c.Identifier
 }
 
-type contractId struct {
+// Id holds the fields shared by all contract ID formats.
+//
+// IDs are equal (==) if all their fields are, including the country which a country policy resolved their country
+// code to, when it doesn't resolve to it without policy: "XK-TNM-C00122045" parsed with a policy accepting "XK" as
+// Kosovo isn't equal to the same ID parsed with a policy accepting it as a test code. Equivalent only compares the
+// contracts they identify.
+type Id struct {
 	countryCode   string
 	partyCode     string
 	instanceValue string
 	checkDigit    rune
-	Reader
+	// country is the country resolved by the country policy the ID was parsed with, if any (see grammar.Match.Country)
+	country c.Country
 }
 
-func Id(countryCode, partyCode, instanceValue string, checkDigit rune) *contractId {
-	return &contractId{
+// NewId returns an Id made of the provided fields, as they are: it doesn't validate nor normalize them.
+// A checkDigit of 0 means that the ID has no check digit.
+func NewId(countryCode, partyCode, instanceValue string, checkDigit rune) Id {
+	return Id{
 		countryCode:   countryCode,
 		partyCode:     partyCode,
 		instanceValue: instanceValue,
@@ -43,81 +55,178 @@ func Id(countryCode, partyCode, instanceValue string, checkDigit rune) *contract
 }
 
 // CountryCode returns the country code
-func (id *contractId) CountryCode() string {
+func (id Id) CountryCode() string {
 	return id.countryCode
 }
 
+// Country returns the country of the country code, or the zero Country if it is unknown. The country resolved by the
+// country policy the ID was parsed with, if any, is returned for aliases, user-assigned and test codes.
+func (id Id) Country() c.Country {
+	if !id.country.IsZero() {
+		return id.country
+	}
+
+	return c.CountryOf(id.countryCode)
+}
+
 // PartyCode returns the party code
-func (id *contractId) PartyCode() string {
+func (id Id) PartyCode() string {
 	return id.partyCode
 }
 
 // InstanceValue returns the instance value
-func (id *contractId) InstanceValue() string {
+func (id Id) InstanceValue() string {
 	return id.instanceValue
 }
 
-// CheckDigit returns the check digit
-func (id *contractId) CheckDigit() rune {
+// CheckDigit returns the check digit, or 0 if the ID has none
+func (id Id) CheckDigit() rune {
 	return id.checkDigit
 }
 
+// HasCheckDigit returns true if the ID has a check digit
+func (id Id) HasCheckDigit() bool {
+	return id.checkDigit != 0
+}
+
+// IsZero returns true if this is the zero value, i.e. none of its fields has been set
+func (id Id) IsZero() bool {
+	return id == Id{}
+}
+
 // PartyId returns the party ID
-func (id *contractId) PartyId() string {
+func (id Id) PartyId() string {
+	if id.IsZero() {
+		return ""
+	}
+
 	return id.CountryCode() + "-" + id.PartyCode()
 }
 
 // CompactPartyId returns the party ID without separator
-func (id *contractId) CompactPartyId() string {
+func (id Id) CompactPartyId() string {
 	return id.CountryCode() + id.PartyCode()
 }
 
-// String returns a canonical contract ID string representation
-func (id *contractId) String() string {
-	result := fmt.Sprintf("%s-%s-%s", id.CountryCode(), id.PartyCode(), id.InstanceValue())
-	if id.CheckDigit() != '0' {
-		result = fmt.Sprintf("%s-%c", result, id.CheckDigit())
+// Values returns the values of the fields of id, in the order of the segments of contract ID grammars: country code,
+// party code, instance value and check digit (empty if absent)
+func Values(id Id) []string {
+	checkDigit := ""
+	if id.HasCheckDigit() {
+		checkDigit = string(id.CheckDigit())
 	}
 
-	return result
+	return []string{id.CountryCode(), id.PartyCode(), id.InstanceValue(), checkDigit}
 }
 
-// CompactString returns a contract ID string without separators
-func (id *contractId) CompactString() string {
-	return strings.ReplaceAll(id.String(), "-", "")
-}
+// FromValues returns an Id made of values, in the order of the segments of contract ID grammars (see Values)
+func FromValues(values []string) Id {
+	var checkDigit rune
+	if len(values[3]) > 0 {
+		checkDigit = rune(values[3][0])
+	}
 
-// CompactStringNoCheckDigit returns a contract ID string without separators nor check digit
-func (id *contractId) CompactStringNoCheckDigit() string {
-	compact := id.CompactString()
-	return compact[:len(compact)-1]
+	return NewId(values[0], values[1], values[2], checkDigit)
 }
 
-// ValidateNoCheckDigit validates provided inputs
-func ValidateNoCheckDigit(countryCode, partyCode, instance string, instanceMaxLength int) error {
-	err := v.Validate(
-		countryCode,
-		v.Required,
-		v.Length(2, 2),
-		v.By(
-			func(value interface{}) error {
-				if !c.IsValidCountryCode(value.(string)) {
-					return fmt.Errorf("country code '%s' is not valid", value.(string))
-				}
-				return nil
-			}),
+// FromMatch returns an Id made of the values matched in input by a contract ID grammar, in upper case.
+// It doesn't allocate if they already are in upper case.
+func FromMatch(input string, m grammar.Match) Id {
+	var checkDigit rune
+	if value := m.Value(input, 3); len(value) > 0 {
+		checkDigit = rune(value[0])
+		if checkDigit >= 'a' && checkDigit <= 'z' {
+			checkDigit -= 'a' - 'A'
+		}
+	}
+
+	id := NewId(
+		strings.ToUpper(m.Value(input, 0)),
+		strings.ToUpper(m.Value(input, 1)),
+		strings.ToUpper(m.Value(input, 2)),
+		checkDigit,
 	)
+	id.country = m.Country()
+
+	return id
+}
+
+// Validate validates the fields of id according to g, in upper case, returning a common.ValidationErrors listing every
+// invalid one. Its country code is accepted if it has been resolved by the country policy id was parsed with.
+func Validate(g *grammar.Grammar, id Id) error {
+	return g.ValidateWith(Values(id), grammar.ParseOptions{
+		RejectLowercase: true,
+		Countries:       c.Accepting(id.countryCode, id.country),
+	})
+}
+
+// WithComputedCheckDigit returns a copy of id, in upper case, with a check digit computed by g from its other fields,
+// replacing the existing one (if any); returns an error if they aren't valid according to g. Its country code is
+// accepted if it has been resolved by the country policy id was parsed with.
+func WithComputedCheckDigit(g *grammar.Grammar, id Id) (Id, error) {
+	values := Values(id)
+	values[3] = ""
+	opts := grammar.ParseOptions{Countries: c.Accepting(id.countryCode, id.country)}
+	if err := g.ValidateWith(values, opts); err != nil {
+		return Id{}, err
+	}
+
+	checkDigit, err := g.ComputeCheckDigit(values)
 	if err != nil {
-		return err
+		return Id{}, err
 	}
 
-	if err := v.Validate(partyCode, v.Required, v.Length(3, 3)); err != nil {
-		return err
+	computed := NewId(strings.ToUpper(values[0]), strings.ToUpper(values[1]), strings.ToUpper(values[2]), checkDigit)
+	computed.country = id.country
+
+	return computed, nil
+}
+
+// Format returns the string representation of id according to g, joining its fields with separator.
+// The check digit is only included if present and withCheckDigit is true.
+func Format(g *grammar.Grammar, id Id, separator string, withCheckDigit bool) string {
+	if id.IsZero() {
+		return ""
 	}
 
-	if err := v.Validate(instance, v.Required, v.Length(1, instanceMaxLength)); err != nil {
-		return err
+	values := Values(id)
+	if !withCheckDigit {
+		values[3] = ""
 	}
 
-	return nil
+	return g.Join(values, separator)
+}
+
+// ValidateNoCheckDigit validates provided inputs, returning a common.ValidationErrors listing every invalid one
+//
+// Deprecated: use the Validate method of the grammar of the format (e.g. iso.Grammar), which also checks the exact
+// length of the instance value and the check digit.
+func ValidateNoCheckDigit(countryCode, partyCode, instance string, instanceMaxLength int) error {
+	return legacyGrammar(instanceMaxLength).Validate([]string{countryCode, partyCode, instance, ""})
+}
+
+// ValidateId validates the fields of an already built ID, which must also be in their canonical form:
+// upper case ASCII letters and digits only. It returns a common.ValidationErrors listing every invalid field.
+//
+// Deprecated: use the Validate method of the ID (e.g. iso.ContractId.Validate), which also checks the exact length of
+// the instance value and the check digit.
+func ValidateId(id Id, instanceMaxLength int) error {
+	values := Values(id)
+	values[3] = ""
+
+	return legacyGrammar(instanceMaxLength).ValidateCanonical(values)
+}
+
+// legacyGrammar describes contract IDs whose instance value has 1 to instanceMaxLength characters, as validated
+// before grammars were introduced
+func legacyGrammar(instanceMaxLength int) *grammar.Grammar {
+	return &grammar.Grammar{
+		Kind: c.KindContract,
+		Segments: []grammar.Segment{
+			grammar.CountryCode,
+			{Field: c.FieldPartyCode, Alphabet: grammar.Alphanumeric, MinLength: 3, MaxLength: 3},
+			{Field: c.FieldInstance, Alphabet: grammar.Alphanumeric, MinLength: 1, MaxLength: instanceMaxLength},
+			grammar.CheckDigit,
+		},
+	}
 }
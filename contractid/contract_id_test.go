@@ -0,0 +1,24 @@
+package contractid_test
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	c "mobilityid/common"
+	"mobilityid/contractid"
+	"testing"
+)
+
+func TestValidateNoCheckDigit(t *testing.T) {
+	assert.Nil(t, contractid.ValidateNoCheckDigit("nl", "tnm", "0012204", 9))
+
+	err := contractid.ValidateNoCheckDigit("XX", "TN", "0012204", 6)
+
+	var errs c.ValidationErrors
+	assert.True(t, errors.As(err, &errs))
+	assert.Equal(t, []c.Field{c.FieldCountryCode, c.FieldPartyCode, c.FieldInstance}, errs.Fields())
+}
+
+func TestValidateId(t *testing.T) {
+	assert.Nil(t, contractid.ValidateId(contractid.NewId("NL", "TNM", "0012204", 0), 9))
+	assert.True(t, errors.Is(contractid.ValidateId(contractid.NewId("nl", "TNM", "0012204", 0), 9), c.ErrInvalidCountryCode))
+}
@@ -3,30 +3,218 @@ package convert
 import (
 	"errors"
 	"fmt"
+	c "mobilityid/common"
+	"mobilityid/contractid"
 	"mobilityid/contractid/din"
 	"mobilityid/contractid/emi3"
 	"mobilityid/contractid/iso"
 	"strings"
 )
 
-// DinToEmi3 converts a DIN contract ID to its EMI3 equivalent
-func DinToEmi3(id *din.ContractId) (*emi3.ContractId, error) {
-	return emi3.NewContractIdNoCheckDigit(id.CountryCode(), id.PartyCode(), fmt.Sprintf("0%s%c", id.InstanceValue(), id.CheckDigit()))
+// Reasons why a conversion isn't possible, to be matched with errors.Is against the errors returned by conversions
+var (
+	// ErrInstanceTooLong is returned when the instance value doesn't fit in the instance value of the target format
+	ErrInstanceTooLong = errors.New("instance value is too long")
+	// ErrNonZeroLeadingDigit is returned when converting to DIN an EMI3 instance value whose first character isn't
+	// '0', which only leaves room for the DIN instance value and check digit; it also matches ErrInstanceTooLong.
+	ErrNonZeroLeadingDigit = fmt.Errorf("%w: EMI3 instance value doesn't start with '0'", ErrInstanceTooLong)
+	// ErrNotCInstance is returned when converting to EMI3 an ISO instance value which doesn't start with 'C'
+	ErrNotCInstance = errors.New("ISO instance value doesn't start with 'C'")
+	// ErrNoConversion is returned when no sequence of conversions leads to the target format
+	ErrNoConversion = errors.New("no conversion available")
+)
+
+// Error reports a contract ID which can't be converted to another format
+type Error struct {
+	Id     string
+	From   c.Format
+	To     c.Format
+	Reason error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("cannot convert %v contract ID %v to %v: %v", e.From, e.Id, e.To, e.Reason)
+}
+
+// Unwrap returns the reason why the conversion isn't possible
+func (e *Error) Unwrap() error {
+	return e.Reason
+}
+
+// DinToEmi3 converts a DIN contract ID to its EMI3 equivalent; the DIN check digit is computed, if missing.
+func DinToEmi3(id din.ContractId) (emi3.ContractId, error) {
+	if !id.HasCheckDigit() {
+		withCheckDigit, err := id.WithComputedCheckDigit()
+		if err != nil {
+			return emi3.ContractId{}, &Error{Id: id.String(), From: c.FormatDin, To: c.FormatEmi3, Reason: err}
+		}
+
+		id = withCheckDigit
+	}
+
+	result, err := emi3.NewContractIdNoCheckDigit(id.CountryCode(), id.PartyCode(), fmt.Sprintf("0%s%c", id.InstanceValue(), id.CheckDigit()))
+	if err != nil {
+		return emi3.ContractId{}, &Error{Id: id.String(), From: c.FormatDin, To: c.FormatEmi3, Reason: err}
+	}
+
+	return result, nil
 }
 
-// Emi3ToDin converts an EMI3 contract ID to its DIN equivalent, if possible
-func Emi3ToDin(id *emi3.ContractId) (*din.ContractId, error) {
+// Emi3ToDin converts an EMI3 contract ID to its DIN equivalent, if possible: its instance value must start with '0',
+// followed by the DIN instance value and check digit.
+func Emi3ToDin(id emi3.ContractId) (din.ContractId, error) {
 	if !strings.HasPrefix(id.InstanceValue(), "0") {
-		return nil, errors.New("cannot convert to DIN: instance value is too long")
+		return din.ContractId{}, &Error{Id: id.String(), From: c.FormatEmi3, To: c.FormatDin, Reason: ErrNonZeroLeadingDigit}
 	}
 
 	dinInstance := id.InstanceValue()[1:7]
 	dinCheckDigit := id.InstanceValue()[7:8][0]
 
-	return din.NewContractId(id.CountryCode(), id.PartyCode(), dinInstance, rune(dinCheckDigit))
+	result, err := din.NewContractId(id.CountryCode(), id.PartyCode(), dinInstance, rune(dinCheckDigit))
+	if err != nil {
+		return din.ContractId{}, &Error{Id: id.String(), From: c.FormatEmi3, To: c.FormatDin, Reason: err}
+	}
+
+	return result, nil
+}
+
+// Emi3ToIso converts an EMI3 contract ID to its ISO equivalent, if possible; the check digit is computed, if missing.
+func Emi3ToIso(id emi3.ContractId) (iso.ContractId, error) {
+	var result iso.ContractId
+	var err error
+	if !id.HasCheckDigit() {
+		result, err = iso.NewContractIdNoCheckDigit(id.CountryCode(), id.PartyCode(), fmt.Sprintf("C%s", id.InstanceValue()))
+	} else {
+		result, err = iso.NewContractId(id.CountryCode(), id.PartyCode(), fmt.Sprintf("C%s", id.InstanceValue()), id.CheckDigit())
+	}
+	if err != nil {
+		return iso.ContractId{}, &Error{Id: id.String(), From: c.FormatEmi3, To: c.FormatIso, Reason: err}
+	}
+
+	return result, nil
+}
+
+// IsoToEmi3 converts an ISO contract ID to its EMI3 equivalent, if possible: its instance value must start with 'C',
+// followed by the EMI3 instance value. Both formats share the same check digit, which is computed, if missing.
+func IsoToEmi3(id iso.ContractId) (emi3.ContractId, error) {
+	if !strings.HasPrefix(id.InstanceValue(), "C") {
+		return emi3.ContractId{}, &Error{Id: id.String(), From: c.FormatIso, To: c.FormatEmi3, Reason: ErrNotCInstance}
+	}
+
+	var result emi3.ContractId
+	var err error
+	if !id.HasCheckDigit() {
+		result, err = emi3.NewContractIdNoCheckDigit(id.CountryCode(), id.PartyCode(), id.InstanceValue()[1:])
+	} else {
+		result, err = emi3.NewContractId(id.CountryCode(), id.PartyCode(), id.InstanceValue()[1:], id.CheckDigit())
+	}
+	if err != nil {
+		return emi3.ContractId{}, &Error{Id: id.String(), From: c.FormatIso, To: c.FormatEmi3, Reason: err}
+	}
+
+	return result, nil
 }
 
-// Emi3ToIso converts an EMI3 contract ID to its ISO equivalent, if possible
-func Emi3ToIso(id *emi3.ContractId) (*iso.ContractId, error) {
-	return iso.NewContractId(id.CountryCode(), id.PartyCode(), fmt.Sprintf("C%s", id.InstanceValue()), id.CheckDigit())
+// conversion converts a contract ID from a format to another one, in a single step
+type conversion struct {
+	from, to c.Format
+	convert  func(id contractid.Reader) (contractid.Reader, error)
+}
+
+// conversions are the edges of the conversion graph, in order of preference
+var conversions = []conversion{
+	{c.FormatDin, c.FormatEmi3, func(id contractid.Reader) (contractid.Reader, error) {
+		return step(id, din.Parse, DinToEmi3)
+	}},
+	{c.FormatEmi3, c.FormatDin, func(id contractid.Reader) (contractid.Reader, error) {
+		return step(id, emi3.Parse, Emi3ToDin)
+	}},
+	{c.FormatEmi3, c.FormatIso, func(id contractid.Reader) (contractid.Reader, error) {
+		return step(id, emi3.Parse, Emi3ToIso)
+	}},
+	{c.FormatIso, c.FormatEmi3, func(id contractid.Reader) (contractid.Reader, error) {
+		return step(id, iso.Parse, IsoToEmi3)
+	}},
+}
+
+// step applies convert to id, which is parsed from its string representation if it isn't of type F already
+func step[F, T contractid.Reader](id contractid.Reader, parse func(string) (F, error), convert func(F) (T, error)) (contractid.Reader, error) {
+	from, ok := id.(F)
+	if !ok {
+		var err error
+		if from, err = parse(id.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	to, err := convert(from)
+	if err != nil {
+		return nil, err
+	}
+
+	return to, nil
+}
+
+// To converts id to the target format, following the shortest path through the conversion graph: DIN and ISO
+// contract IDs are converted to one another through EMI3. Check digits are computed where the target format needs
+// them. id is returned as is if it is in the target format already.
+//
+// The returned error is an *Error, whose Reason tells why the first impossible step failed, e.g. ErrNotCInstance.
+func To(id contractid.Reader, target c.Format) (contractid.Reader, error) {
+	if id.Format() == target {
+		return id, nil
+	}
+
+	path := shortestPath(id.Format(), target)
+	if path == nil {
+		return nil, &Error{Id: id.String(), From: id.Format(), To: target, Reason: ErrNoConversion}
+	}
+
+	for _, conv := range path {
+		converted, err := conv.convert(id)
+		if err != nil {
+			var convErr *Error
+			if !errors.As(err, &convErr) {
+				err = &Error{Id: id.String(), From: conv.from, To: conv.to, Reason: err}
+			}
+
+			return nil, err
+		}
+
+		id = converted
+	}
+
+	return id, nil
+}
+
+// shortestPath returns the conversions leading from a format to another one, with a breadth-first search of the
+// conversion graph; it returns nil if there is none.
+func shortestPath(from, to c.Format) []conversion {
+	previous := map[c.Format]conversion{}
+	queue := []c.Format{from}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if current == to {
+			var path []conversion
+			for current != from {
+				conv := previous[current]
+				path = append([]conversion{conv}, path...)
+				current = conv.from
+			}
+
+			return path
+		}
+
+		for _, conv := range conversions {
+			if _, visited := previous[conv.to]; conv.from == current && conv.to != from && !visited {
+				previous[conv.to] = conv
+				queue = append(queue, conv.to)
+			}
+		}
+	}
+
+	return nil
 }
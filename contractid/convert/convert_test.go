@@ -1,9 +1,13 @@
 package convert
 
 import (
+	"errors"
 	"github.com/stretchr/testify/assert"
+	c "mobilityid/common"
+	"mobilityid/contractid"
 	"mobilityid/contractid/din"
 	"mobilityid/contractid/emi3"
+	"mobilityid/contractid/iso"
 	"testing"
 )
 
@@ -22,6 +26,16 @@ func TestDinToEmi3(t *testing.T) {
 		assert.Nil(t, err)
 		assert.Equal(t, "NL-TNM-C00122045-K", emi3ContractId.String())
 	})
+
+	t.Run("computes the DIN check digit, if missing", func(t *testing.T) {
+		dinContractId, err := din.Parse("NL-TNM-012204")
+		assert.Nil(t, err)
+
+		emi3ContractId, err := DinToEmi3(dinContractId)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "NL-TNM-C00122045-K", emi3ContractId.String())
+	})
 }
 
 func TestEmi3ToDin(t *testing.T) {
@@ -52,6 +66,17 @@ func TestEmi3ToDin(t *testing.T) {
 		_, err = Emi3ToDin(emi3ContractId)
 
 		assert.NotNil(t, err)
+		assert.ErrorIs(t, err, ErrNonZeroLeadingDigit)
+		assert.ErrorIs(t, err, ErrInstanceTooLong)
+	})
+
+	t.Run("returns an error if the last instance character is not the DIN check digit", func(t *testing.T) {
+		emi3ContractId, err := emi3.Parse("NL-TNM-C00122046")
+		assert.Nil(t, err)
+
+		_, err = Emi3ToDin(emi3ContractId)
+
+		assert.ErrorIs(t, err, c.ErrInvalidCheckDigit)
 	})
 }
 
@@ -71,3 +96,106 @@ func TestEmi3ToIso(t *testing.T) {
 		assert.Equal(t, "NL-TNM-C00122045-K", isoContractId.String())
 	})
 }
+
+func TestIsoToEmi3(t *testing.T) {
+	t.Run("converts an ISO into a valid EMI3 contract id if its instance starts with C", func(t *testing.T) {
+		isoContractId, err := iso.Parse("NL-TNM-C00122045-K")
+		assert.Nil(t, err)
+
+		emi3ContractId, err := IsoToEmi3(isoContractId)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "NL-TNM-C00122045-K", emi3ContractId.String())
+		assert.Equal(t, "00122045", emi3ContractId.InstanceValue())
+	})
+
+	t.Run("computes the check digit, if missing", func(t *testing.T) {
+		isoContractId, err := iso.Parse("NLTNMC00122045")
+		assert.Nil(t, err)
+
+		emi3ContractId, err := IsoToEmi3(isoContractId)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 'K', emi3ContractId.CheckDigit())
+	})
+
+	t.Run("returns an error if its instance does not start with C", func(t *testing.T) {
+		isoContractId, err := iso.NewContractIdNoCheckDigit("NL", "TNM", "D00122045")
+		assert.Nil(t, err)
+
+		_, err = IsoToEmi3(isoContractId)
+
+		var convErr *Error
+		assert.ErrorAs(t, err, &convErr)
+		assert.ErrorIs(t, err, ErrNotCInstance)
+		assert.Equal(t, c.FormatIso, convErr.From)
+		assert.Equal(t, c.FormatEmi3, convErr.To)
+	})
+}
+
+func TestTo(t *testing.T) {
+	dinId, _ := din.Parse("NL-TNM-012204-5")
+	emi3Id, _ := emi3.Parse("NL-TNM-C00122045-K")
+	isoId, _ := iso.Parse("NL-TNM-C00122045-K")
+	isoNoCheckDigit, _ := iso.Parse("NL-TNM-C00122045")
+	dinNoCheckDigit, _ := din.Parse("NL-TNM-012204")
+	notCInstance, _ := iso.Parse("NL-TNM-D00122045")
+	nonZeroLeadingDigit, _ := iso.Parse("NL-TNM-C33122045")
+
+	cases := []struct {
+		name     string
+		id       contractid.Reader
+		target   c.Format
+		expected contractid.Reader
+		err      error
+	}{
+		{"DIN to EMI3", dinId, c.FormatEmi3, emi3Id, nil},
+		{"DIN to ISO", dinId, c.FormatIso, isoId, nil},
+		{"DIN to DIN", dinId, c.FormatDin, dinId, nil},
+		{"DIN without check digit to ISO", dinNoCheckDigit, c.FormatIso, isoId, nil},
+		{"EMI3 to DIN", emi3Id, c.FormatDin, dinId, nil},
+		{"EMI3 to ISO", emi3Id, c.FormatIso, isoId, nil},
+		{"ISO to EMI3", isoId, c.FormatEmi3, emi3Id, nil},
+		{"ISO to DIN", isoId, c.FormatDin, dinId, nil},
+		{"ISO without check digit to DIN", isoNoCheckDigit, c.FormatDin, dinId, nil},
+		{"ISO not starting with C to DIN", notCInstance, c.FormatDin, nil, ErrNotCInstance},
+		{"ISO with a non-zero leading digit to DIN", nonZeroLeadingDigit, c.FormatDin, nil, ErrNonZeroLeadingDigit},
+		{"ISO to unknown format", isoId, c.Format(0), nil, ErrNoConversion},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := To(tc.id, tc.target)
+
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				assert.Nil(t, result)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestTo_ParsesOtherImplementations(t *testing.T) {
+	dinId, _ := din.Parse("NL-TNM-012204-5")
+
+	result, err := To(wrapper{dinId}, c.FormatIso)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "NL-TNM-C00122045-K", result.String())
+}
+
+func TestError(t *testing.T) {
+	err := &Error{Id: "NL-TNM-D00122045", From: c.FormatIso, To: c.FormatEmi3, Reason: ErrNotCInstance}
+
+	assert.Equal(t, "cannot convert ISO contract ID NL-TNM-D00122045 to EMI3: ISO instance value doesn't start with 'C'", err.Error())
+	assert.True(t, errors.Is(err, ErrNotCInstance))
+}
+
+// wrapper is a contract ID implementation other than the ones of the format packages
+type wrapper struct {
+	din.ContractId
+}
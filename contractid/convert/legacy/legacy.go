@@ -0,0 +1,60 @@
+// Package legacy ports the direct conversions between ISO and DIN contract IDs, which are deprecated in the original
+// Scala library. They are opt-in, for systems which still have to reconcile DIN and ISO records: they follow the rules
+// of the Scala conversions, and their output is the same as the one of convert.To, which converts them through EMI3.
+package legacy
+
+import (
+	c "mobilityid/common"
+	"mobilityid/contractid/convert"
+	"mobilityid/contractid/din"
+	"mobilityid/contractid/iso"
+	"strings"
+)
+
+// DinToIso converts a DIN contract ID to its ISO equivalent, whose instance value is "C0" followed by the DIN instance
+// value and check digit; the DIN check digit is computed, if missing, and so is the ISO one.
+//
+// Deprecated: DIN contract IDs should be converted to EMI3, with convert.DinToEmi3, or to any format with convert.To.
+func DinToIso(id din.ContractId) (iso.ContractId, error) {
+	if !id.HasCheckDigit() {
+		withCheckDigit, err := id.WithComputedCheckDigit()
+		if err != nil {
+			return iso.ContractId{}, &convert.Error{Id: id.String(), From: c.FormatDin, To: c.FormatIso, Reason: err}
+		}
+
+		id = withCheckDigit
+	}
+
+	result, err := iso.NewContractIdNoCheckDigit(id.CountryCode(), id.PartyCode(), "C0"+id.InstanceValue()+string(id.CheckDigit()))
+	if err != nil {
+		return iso.ContractId{}, &convert.Error{Id: id.String(), From: c.FormatDin, To: c.FormatIso, Reason: err}
+	}
+
+	return result, nil
+}
+
+// IsoToDin converts an ISO contract ID to its DIN equivalent, if possible: its instance value must start with "C0",
+// followed by the DIN instance value and check digit. The ISO check digit, if any, is not carried over.
+//
+// Deprecated: ISO contract IDs should be converted to EMI3, with convert.IsoToEmi3, or to any format with convert.To.
+func IsoToDin(id iso.ContractId) (din.ContractId, error) {
+	instance := id.InstanceValue()
+
+	var reason error
+	switch {
+	case !strings.HasPrefix(instance, "C"):
+		reason = convert.ErrNotCInstance
+	case !strings.HasPrefix(instance, "C0"):
+		reason = convert.ErrNonZeroLeadingDigit
+	}
+	if reason != nil {
+		return din.ContractId{}, &convert.Error{Id: id.String(), From: c.FormatIso, To: c.FormatDin, Reason: reason}
+	}
+
+	result, err := din.NewContractId(id.CountryCode(), id.PartyCode(), instance[2:8], rune(instance[8]))
+	if err != nil {
+		return din.ContractId{}, &convert.Error{Id: id.String(), From: c.FormatIso, To: c.FormatDin, Reason: err}
+	}
+
+	return result, nil
+}
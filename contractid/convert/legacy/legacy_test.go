@@ -0,0 +1,125 @@
+package legacy
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	c "mobilityid/common"
+	"mobilityid/contractid/convert"
+	"mobilityid/contractid/din"
+	"mobilityid/contractid/iso"
+	"testing"
+)
+
+func TestDinToIso(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected string
+	}{
+		{"NL-TNM-012204-5", "NL-TNM-C00122045-K"},
+		{"NL-TNM-012204", "NL-TNM-C00122045-K"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.input, func(t *testing.T) {
+			dinId, err := din.Parse(tc.input)
+			assert.Nil(t, err)
+
+			isoId, err := DinToIso(dinId)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, isoId.String())
+		})
+	}
+}
+
+func TestIsoToDin(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected string
+		err      error
+	}{
+		{"NL-TNM-C00122045-K", "NL-TNM-012204-5", nil},
+		{"NLTNMC00122045", "NL-TNM-012204-5", nil},
+		{"NL-TNM-D00122045", "", convert.ErrNotCInstance},
+		{"NL-TNM-C33122045", "", convert.ErrNonZeroLeadingDigit},
+		{"NL-TNM-C00122046", "", c.ErrInvalidCheckDigit},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.input, func(t *testing.T) {
+			isoId, err := iso.Parse(tc.input)
+			assert.Nil(t, err)
+
+			dinId, err := IsoToDin(isoId)
+
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, dinId.String())
+		})
+	}
+}
+
+// fixtures pair DIN contract IDs with their ISO counterpart. The DIN IDs and check digits are the vectors of the test
+// suite of the Scala library, also used by the checkdigit and convert packages; the ISO check digits are the ones
+// computed by the reference port of its ISO algorithm, in checkdigit/reference_test.go. They aren't outputs of the
+// Scala library, so they only pin the conversions down against independently computed check digits.
+var fixtures = []struct {
+	din string
+	iso string
+}{
+	{"NL-TNM-012204-5", "NL-TNM-C00122045-K"},
+	{"IN-TNM-000071-9", "IN-TNM-C00000719-5"},
+	{"IN-TNM-000110-X", "IN-TNM-C0000110X-R"},
+	{"IN-TNM-000124-0", "IN-TNM-C00001240-K"},
+	{"IN-TNM-000114-6", "IN-TNM-C00001146-J"},
+	{"IN-TNM-000191-5", "IN-TNM-C00001915-S"},
+}
+
+func TestFixtures(t *testing.T) {
+	for _, f := range fixtures {
+		t.Run(f.din, func(t *testing.T) {
+			dinId, err := din.Parse(f.din)
+			assert.Nil(t, err)
+			isoId, err := iso.Parse(f.iso)
+			assert.Nil(t, err)
+
+			toIso, err := DinToIso(dinId)
+			assert.Nil(t, err)
+			assert.Equal(t, isoId, toIso)
+
+			noCheckDigit, err := din.Parse(f.din[:len(f.din)-2])
+			assert.Nil(t, err)
+			toIso, err = DinToIso(noCheckDigit)
+			assert.Nil(t, err)
+			assert.Equal(t, isoId, toIso)
+
+			toDin, err := IsoToDin(isoId)
+			assert.Nil(t, err)
+			assert.Equal(t, dinId, toDin)
+		})
+	}
+}
+
+// TestConversions_MatchConversionsThroughEmi3 checks that the direct conversions are consistent with convert.To
+func TestConversions_MatchConversionsThroughEmi3(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 1000; i++ {
+		dinId := din.Generate(r)
+
+		isoId, err := DinToIso(dinId)
+		assert.Nil(t, err)
+
+		throughEmi3, err := convert.To(dinId, c.FormatIso)
+		assert.Nil(t, err)
+		assert.Equal(t, throughEmi3, isoId)
+
+		back, err := IsoToDin(isoId)
+		assert.Nil(t, err)
+		assert.Equal(t, dinId, back)
+	}
+}
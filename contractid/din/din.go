@@ -1,101 +1,135 @@
 package din
 
 import (
-	"fmt"
+	"math/rand"
+	"mobilityid/checkdigit"
 	c "mobilityid/common"
 	"mobilityid/contractid"
-	"regexp"
-	"strings"
+	"mobilityid/grammar"
 )
 
-const instanceMaxLength = 6
+// Grammar describes the syntax of DIN contract IDs, e.g. "NL-TNM-012204-5"
+var Grammar = &grammar.Grammar{
+	Kind:       c.KindContract,
+	Format:     c.FormatDin,
+	Separators: "-*",
+	Segments: []grammar.Segment{
+		grammar.CountryCode,
+		{Field: c.FieldPartyCode, Alphabet: grammar.Alphanumeric, MinLength: 3, MaxLength: 3},
+		{Field: c.FieldInstance, Alphabet: grammar.Alphanumeric, MinLength: 6, MaxLength: 6},
+		grammar.CheckDigit,
+	},
+	CheckDigit: checkdigit.Din,
+}
 
-var regex = regexp.MustCompile(fmt.Sprintf("^(?P<country>%v)(?:[*-]?)(?P<party>%v)(?:[*-]?)(?P<instance>%v)(?:(?:[*-]?)(?P<check>%v))?$", c.CountryCodeRegex, c.PartyCodeRegex, "([A-Za-z0-9]{6})", c.CheckDigitRegex))
+// parser parses DIN contract IDs, as described by Grammar
+var parser = grammar.Parser[ContractId]{
+	Grammar: Grammar,
+	Build: func(input string, m grammar.Match) ContractId {
+		return ContractId{contractid.FromMatch(input, m)}
+	},
+}
 
+func init() {
+	contractid.RegisterFormat(c.FormatDin, parseReader)
+}
+
+func parseReader(input string, opts grammar.ParseOptions) (contractid.Reader, error) {
+	return ParseWith(input, opts)
+}
+
+// ContractId represents a DIN contract identifier
 type ContractId struct {
-	contractid.Reader
+	contractid.Id
 }
 
-// NewContractIdNoCheckDigit returns a DIN contract ID complete of check digit, if provided input is valid; returns an error otherwise.
-func NewContractIdNoCheckDigit(countryCode, partyCode, instance string) (*ContractId, error) {
-	if err := contractid.ValidateNoCheckDigit(countryCode, partyCode, instance, instanceMaxLength); err != nil {
-		return nil, err
-	}
+// Kind returns the kind of this identifier
+func (id ContractId) Kind() c.Kind {
+	return Grammar.Kind
+}
 
-	return &ContractId{
-		contractid.Id(
-			strings.ToUpper(countryCode),
-			strings.ToUpper(partyCode),
-			strings.ToUpper(instance),
-			ComputeCheckDigit(countryCode+partyCode+instance),
-		),
-	}, nil
+// Format returns the format of this identifier
+func (id ContractId) Format() c.Format {
+	return Grammar.Format
 }
 
-// NewContractId returns a DIN contract ID, if provided input is valid; returns an error otherwise.
-func NewContractId(countryCode, partyCode, instance string, checkDigit rune) (*ContractId, error) {
-	id, err := NewContractIdNoCheckDigit(countryCode, partyCode, instance)
-	if err != nil {
-		return nil, err
-	}
+// String returns a canonical contract ID string representation, including the check digit only if present
+func (id ContractId) String() string {
+	return contractid.Format(Grammar, id.Id, Grammar.Separator(), true)
+}
+
+// CompactString returns a contract ID string without separators, including the check digit only if present
+func (id ContractId) CompactString() string {
+	return contractid.Format(Grammar, id.Id, "", true)
+}
+
+// CompactStringNoCheckDigit returns a contract ID string without separators nor check digit
+func (id ContractId) CompactStringNoCheckDigit() string {
+	return contractid.Format(Grammar, id.Id, "", false)
+}
+
+// NewContractIdNoCheckDigit returns a DIN contract ID complete of check digit, if provided input is valid; returns an error otherwise.
+func NewContractIdNoCheckDigit(countryCode, partyCode, instance string) (ContractId, error) {
+	id, err := contractid.WithComputedCheckDigit(Grammar, contractid.NewId(countryCode, partyCode, instance, 0))
+	return ContractId{id}, err
+}
 
-	if checkDigit != id.CheckDigit() {
-		return nil, fmt.Errorf("provided check digit '%c' doesn't match computed one '%c'", checkDigit, id.CheckDigit())
+// NewContractId returns a DIN contract ID, if provided input is valid; returns an error otherwise.
+func NewContractId(countryCode, partyCode, instance string, checkDigit rune) (ContractId, error) {
+	if err := Grammar.Validate([]string{countryCode, partyCode, instance, string(checkDigit)}); err != nil {
+		return ContractId{}, err
 	}
 
-	return id, nil
+	return NewContractIdNoCheckDigit(countryCode, partyCode, instance)
 }
 
 // Parse parses the input string into a DIN contract ID, if it is valid; returns an error otherwise.
 // A check digit will only be present, in returned struct, if the provided string contained it.
-func Parse(input string) (*ContractId, error) {
-	groups := regex.FindStringSubmatch(input)
+// It doesn't allocate if input is valid and in upper case.
+func Parse(input string) (ContractId, error) {
+	return ParseWith(input, grammar.ParseOptions{})
+}
 
-	countryCode, err := c.ExtractAndUpcaseGroup(regex, groups, "country", true)
-	if err != nil {
-		return nil, fmt.Errorf("not a DIN contract ID: %v", input)
-	}
-	partyCode, err := c.ExtractAndUpcaseGroup(regex, groups, "party", true)
-	if err != nil {
-		return nil, fmt.Errorf("not a DIN contract ID: %v", input)
-	}
-	instance, err := c.ExtractAndUpcaseGroup(regex, groups, "instance", true)
-	if err != nil {
-		return nil, fmt.Errorf("not a DIN contract ID: %v", input)
-	}
-	check, err := c.ExtractAndUpcaseGroup(regex, groups, "check", false)
-	if err != nil {
-		return nil, fmt.Errorf("not a DIN contract ID: %v", input)
-	}
+// ParseWith parses input like Parse, as strictly as required by opts
+func ParseWith(input string, opts grammar.ParseOptions) (ContractId, error) {
+	return parser.ParseWith(input, opts)
+}
 
-	var checkDigit rune
-	if len(check) > 0 {
-		checkDigit = rune(check[0])
-		if err := validate(countryCode, partyCode, instance, checkDigit); err != nil {
-			return nil, err
-		}
-	} else if err := contractid.ValidateNoCheckDigit(countryCode, partyCode, instance, instanceMaxLength); err != nil {
-		return nil, err
-	}
+// ParseBytes parses input like Parse; it allocates once, if input is valid (see grammar.Parser.ParseBytes)
+func ParseBytes(input []byte) (ContractId, error) {
+	return parser.ParseBytes(input)
+}
 
-	return &ContractId{
-		contractid.Id(
-			countryCode,
-			partyCode,
-			instance,
-			ComputeCheckDigit(countryCode+partyCode+instance),
-		),
-	}, nil
+// Check returns the error Parse would return for input, without building the ID: it doesn't allocate if input is valid.
+func Check(input string) error {
+	return parser.Check(input)
 }
 
-func validate(countryCode, partyCode, instance string, checkDigit rune) error {
-	if err := contractid.ValidateNoCheckDigit(countryCode, partyCode, instance, instanceMaxLength); err != nil {
-		return err
-	}
+// CheckBytes is like Check, but takes a byte slice
+func CheckBytes(input []byte) error {
+	return parser.CheckBytes(input)
+}
 
-	if checkDigit != ComputeCheckDigit(countryCode+partyCode+instance) {
-		return fmt.Errorf("check digit '%c' is invalid", checkDigit)
-	}
+// Suggest lists the corrections of a single typo turning input into a DIN contract ID complete of check digit, most
+// likely first; it returns nothing if input is already valid.
+func Suggest(input string) []contractid.Suggestion {
+	return contractid.Suggest(Grammar, input, parseReader)
+}
+
+// Generate returns a random, valid, DIN contract ID complete of check digit
+func Generate(r *rand.Rand) ContractId {
+	return ContractId{contractid.FromValues(Grammar.Generate(r))}
+}
+
+// WithComputedCheckDigit returns a copy of this ID with a check digit computed from its other fields, replacing the
+// existing one (if any); returns an error if the ID is not valid.
+func (id ContractId) WithComputedCheckDigit() (ContractId, error) {
+	computed, err := contractid.WithComputedCheckDigit(Grammar, id.Id)
+	return ContractId{computed}, err
+}
 
-	return nil
+// Validate validates the fields of this ID, including its check digit if present, returning a
+// common.ValidationErrors listing every invalid one.
+func (id ContractId) Validate() error {
+	return contractid.Validate(Grammar, id.Id)
 }
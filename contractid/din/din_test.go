@@ -1,8 +1,12 @@
 package din
 
 import (
+	"errors"
 	"github.com/stretchr/testify/assert"
+	"math/rand"
+	c "mobilityid/common"
 	"mobilityid/contractid"
+	"mobilityid/grammar"
 	"testing"
 )
 
@@ -13,7 +17,7 @@ var (
 		InstanceValue: "000071",
 		CheckDigit:    '9',
 	}
-	expectedId                = &ContractId{contractid.Id(input.CountryCode, input.PartyCode, input.InstanceValue, input.CheckDigit)}
+	expectedId                = ContractId{contractid.NewId(input.CountryCode, input.PartyCode, input.InstanceValue, input.CheckDigit)}
 	assertValidId             = contractid.NewAssertValidId(input.CountryCode, input.PartyCode, input.InstanceValue, input.CheckDigit)
 	assertValidIdNoCheckDigit = contractid.NewAssertValidIdNoCheckDigit(input.CountryCode, input.PartyCode, input.InstanceValue)
 )
@@ -207,3 +211,164 @@ func TestNewContractIdNoCheckDigit(t *testing.T) {
 		})
 	}
 }
+
+func TestContractId_IsZero(t *testing.T) {
+	t.Run("returns true for the zero value, which can be used without panicking", func(t *testing.T) {
+		id := ContractId{}
+
+		assert.True(t, id.IsZero())
+		assert.Equal(t, "", id.String())
+		assert.Equal(t, "", id.CompactStringNoCheckDigit())
+	})
+
+	t.Run("returns false for a non-empty ID", func(t *testing.T) {
+		assert.False(t, expectedId.IsZero())
+	})
+}
+
+func TestContractId_Equality(t *testing.T) {
+	t.Run("compares IDs by value, so that they can be used as map keys", func(t *testing.T) {
+		parsed, err := Parse("IN*TNM*000071*9")
+		assert.Nil(t, err)
+
+		assert.True(t, parsed == expectedId)
+		assert.True(t, map[ContractId]bool{parsed: true}[expectedId])
+	})
+}
+
+func TestContractId_Validate(t *testing.T) {
+	cases := []struct {
+		name        string
+		id          ContractId
+		expectedErr bool
+	}{
+		{
+			name: "accepts a valid ID",
+			id:   expectedId,
+		},
+		{
+			name:        "rejects the zero value",
+			id:          ContractId{},
+			expectedErr: true,
+		},
+		{
+			name:        "rejects an ID with invalid characters",
+			id:          ContractId{contractid.NewId(input.CountryCode, input.PartyCode, "0000-1", input.CheckDigit)},
+			expectedErr: true,
+		},
+		{
+			name:        "rejects an ID with an invalid check digit",
+			id:          ContractId{contractid.NewId(input.CountryCode, input.PartyCode, input.InstanceValue, 'A')},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.id.Validate()
+
+			assert.Equal(t, test.expectedErr, err != nil)
+		})
+	}
+}
+
+func TestContractId_CheckDigit(t *testing.T) {
+	t.Run("prints a check digit equal to '0'", func(t *testing.T) {
+		id, err := NewContractIdNoCheckDigit("IN", "TNM", "000124")
+		assert.Nil(t, err)
+
+		assert.True(t, id.HasCheckDigit())
+		assert.Equal(t, "IN-TNM-000124-0", id.String())
+		assert.Equal(t, "INTNM000124", id.CompactStringNoCheckDigit())
+	})
+
+	t.Run("does not compute a check digit when parsing an ID without one", func(t *testing.T) {
+		id, err := Parse("IN-TNM-000124")
+		assert.Nil(t, err)
+
+		assert.False(t, id.HasCheckDigit())
+		assert.Equal(t, "IN-TNM-000124", id.String())
+		assert.Equal(t, "INTNM000124", id.CompactStringNoCheckDigit())
+	})
+}
+
+func TestContractId_WithComputedCheckDigit(t *testing.T) {
+	t.Run("computes the missing check digit", func(t *testing.T) {
+		id, err := Parse("IN-TNM-000071")
+		assert.Nil(t, err)
+
+		withCheckDigit, err := id.WithComputedCheckDigit()
+
+		assert.Nil(t, err)
+		assert.Equal(t, expectedId, withCheckDigit)
+	})
+}
+
+func TestContractId_Identifier(t *testing.T) {
+	t.Run("implements the Identifier interface", func(t *testing.T) {
+		var id c.Identifier = expectedId
+
+		assert.Equal(t, c.KindContract, id.Kind())
+		assert.Equal(t, c.FormatDin, id.Format())
+		assert.Equal(t, expectedId.PartyId(), id.PartyId())
+		assert.Equal(t, "IND", id.Country().Alpha3)
+	})
+}
+
+func TestGenerate(t *testing.T) {
+	t.Run("generates valid contract IDs, which can be parsed back", func(t *testing.T) {
+		r := rand.New(rand.NewSource(1))
+
+		for i := 0; i < 100; i++ {
+			id := Generate(r)
+			assert.Nil(t, id.Validate())
+
+			parsed, err := Parse(id.String())
+			assert.Nil(t, err)
+			assert.Equal(t, id, parsed)
+		}
+	})
+}
+
+func TestParseBytes(t *testing.T) {
+	for _, s := range []string{expectedId.String(), expectedId.CompactString()} {
+		id, err := ParseBytes([]byte(s))
+
+		assert.Nil(t, err)
+		assert.Equal(t, expectedId, id)
+		assert.Nil(t, Check(s))
+		assert.Nil(t, CheckBytes([]byte(s)))
+	}
+
+	_, expectedErr := Parse("invalid")
+	_, err := ParseBytes([]byte("invalid"))
+
+	assert.NotNil(t, err)
+	assert.Equal(t, expectedErr, err)
+	assert.Equal(t, expectedErr, Check("invalid"))
+	assert.Equal(t, expectedErr, CheckBytes([]byte("invalid")))
+}
+
+func TestParseWith(t *testing.T) {
+	_, err := ParseWith("IN*TNM*000071*9", grammar.ParseOptions{CanonicalSeparator: true})
+	assert.True(t, errors.Is(err, c.ErrInvalidFormat))
+
+	_, err = ParseWith("IN-TNM-00007-9", grammar.ParseOptions{})
+	assert.True(t, errors.Is(err, c.ErrInvalidInstance))
+	assert.True(t, errors.Is(err, c.ErrWrongLength))
+
+	_, err = ParseWith("IN-TNM-000071", grammar.ParseOptions{RequireCheckDigit: true})
+	assert.True(t, errors.Is(err, c.ErrInvalidCheckDigit))
+
+	id, err := ParseWith("IN-TNM-000071-9", grammar.Strict)
+	assert.Nil(t, err)
+	assert.Equal(t, expectedId, id)
+}
+
+func TestSuggest(t *testing.T) {
+	suggestions := Suggest("IN-TNM-0O0071-9")
+
+	assert.NotEmpty(t, suggestions)
+	assert.Equal(t, "IN-TNM-000071-9", suggestions[0].Input)
+	assert.Equal(t, expectedId, suggestions[0].Id)
+}
@@ -2,7 +2,10 @@ package emi3
 
 import (
 	"github.com/stretchr/testify/assert"
+	"math/rand"
+	c "mobilityid/common"
 	"mobilityid/contractid"
+	"mobilityid/grammar"
 	"testing"
 )
 
@@ -13,7 +16,7 @@ var (
 		InstanceValue: "00122045",
 		CheckDigit:    'K',
 	}
-	expectedId                = &ContractId{contractid.Id(input.CountryCode, input.PartyCode, input.InstanceValue, input.CheckDigit)}
+	expectedId                = ContractId{contractid.NewId(input.CountryCode, input.PartyCode, input.InstanceValue, input.CheckDigit)}
 	assertValidId             = contractid.NewAssertValidId(input.CountryCode, input.PartyCode, input.InstanceValue, input.CheckDigit)
 	assertValidIdNoCheckDigit = contractid.NewAssertValidIdNoCheckDigit(input.CountryCode, input.PartyCode, input.InstanceValue)
 )
@@ -212,3 +215,158 @@ func TestNewContractIdNoCheckDigit(t *testing.T) {
 		})
 	}
 }
+
+func TestContractId_IsZero(t *testing.T) {
+	t.Run("returns true for the zero value, which can be used without panicking", func(t *testing.T) {
+		id := ContractId{}
+
+		assert.True(t, id.IsZero())
+		assert.Equal(t, "", id.String())
+		assert.Equal(t, "", id.CompactStringNoCheckDigit())
+	})
+
+	t.Run("returns false for a non-empty ID", func(t *testing.T) {
+		assert.False(t, expectedId.IsZero())
+	})
+}
+
+func TestContractId_Equality(t *testing.T) {
+	t.Run("compares IDs by value, so that they can be used as map keys", func(t *testing.T) {
+		parsed, err := Parse("NltNMc00122045k")
+		assert.Nil(t, err)
+
+		created, err := NewContractId(input.CountryCode, input.PartyCode, input.InstanceValue, input.CheckDigit)
+		assert.Nil(t, err)
+
+		assert.True(t, parsed == created)
+		assert.True(t, map[ContractId]bool{parsed: true}[created])
+	})
+}
+
+func TestContractId_Validate(t *testing.T) {
+	cases := []struct {
+		name        string
+		id          ContractId
+		expectedErr bool
+	}{
+		{
+			name: "accepts a valid ID",
+			id:   expectedId,
+		},
+		{
+			name:        "rejects the zero value",
+			id:          ContractId{},
+			expectedErr: true,
+		},
+		{
+			name:        "rejects an ID with an instance that is too long",
+			id:          ContractId{contractid.NewId(input.CountryCode, input.PartyCode, "001220450", input.CheckDigit)},
+			expectedErr: true,
+		},
+		{
+			name:        "rejects an ID with an invalid check digit",
+			id:          ContractId{contractid.NewId(input.CountryCode, input.PartyCode, input.InstanceValue, 'A')},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.id.Validate()
+
+			assert.Equal(t, test.expectedErr, err != nil)
+		})
+	}
+}
+
+func TestContractId_CheckDigit(t *testing.T) {
+	t.Run("prints no check digit if absent", func(t *testing.T) {
+		id, err := Parse("NL-TNM-C00122045")
+		assert.Nil(t, err)
+
+		assert.False(t, id.HasCheckDigit())
+		assert.Equal(t, "NL-TNM-C00122045", id.String())
+		assert.Equal(t, "NLTNMC00122045", id.CompactString())
+		assert.Equal(t, "NLTNMC00122045", id.CompactStringNoCheckDigit())
+	})
+}
+
+func TestContractId_WithComputedCheckDigit(t *testing.T) {
+	t.Run("computes the missing check digit", func(t *testing.T) {
+		id, err := Parse("NL-TNM-C00122045")
+		assert.Nil(t, err)
+
+		withCheckDigit, err := id.WithComputedCheckDigit()
+
+		assert.Nil(t, err)
+		assert.Equal(t, expectedId, withCheckDigit)
+	})
+}
+
+func TestContractId_Identifier(t *testing.T) {
+	t.Run("implements the Identifier interface", func(t *testing.T) {
+		var id c.Identifier = expectedId
+
+		assert.Equal(t, c.KindContract, id.Kind())
+		assert.Equal(t, c.FormatEmi3, id.Format())
+		assert.Equal(t, expectedId.PartyId(), id.PartyId())
+		assert.Equal(t, "NLD", id.Country().Alpha3)
+	})
+}
+
+func TestGenerate(t *testing.T) {
+	t.Run("generates valid contract IDs, which can be parsed back", func(t *testing.T) {
+		r := rand.New(rand.NewSource(1))
+
+		for i := 0; i < 100; i++ {
+			id := Generate(r)
+			assert.Nil(t, id.Validate())
+
+			parsed, err := Parse(id.String())
+			assert.Nil(t, err)
+			assert.Equal(t, id, parsed)
+		}
+	})
+}
+
+func TestParseBytes(t *testing.T) {
+	for _, s := range []string{expectedId.String(), expectedId.CompactString()} {
+		id, err := ParseBytes([]byte(s))
+
+		assert.Nil(t, err)
+		assert.Equal(t, expectedId, id)
+		assert.Nil(t, Check(s))
+		assert.Nil(t, CheckBytes([]byte(s)))
+	}
+
+	_, expectedErr := Parse("invalid")
+	_, err := ParseBytes([]byte("invalid"))
+
+	assert.NotNil(t, err)
+	assert.Equal(t, expectedErr, err)
+	assert.Equal(t, expectedErr, Check("invalid"))
+	assert.Equal(t, expectedErr, CheckBytes([]byte("invalid")))
+}
+
+func TestSuggest(t *testing.T) {
+	t.Run("suggests the substitution of a confusable character first", func(t *testing.T) {
+		suggestions := Suggest("NL-TNM-C0O122045-K")
+
+		assert.NotEmpty(t, suggestions)
+		assert.Equal(t, "NL-TNM-C00122045-K", suggestions[0].Input)
+		assert.Equal(t, expectedId, suggestions[0].Id)
+		assert.True(t, suggestions[0].Confusable)
+	})
+
+	t.Run("suggests the transposition of adjacent characters", func(t *testing.T) {
+		suggestions := Suggest("NL-TNM-C01022045-K")
+
+		assert.NotEmpty(t, suggestions)
+		assert.Equal(t, grammar.Transposition, suggestions[0].Kind)
+		assert.Equal(t, expectedId, suggestions[0].Id)
+	})
+
+	t.Run("suggests nothing for a valid ID", func(t *testing.T) {
+		assert.Empty(t, Suggest("NL-TNM-C00122045-K"))
+	})
+}
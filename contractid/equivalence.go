@@ -0,0 +1,144 @@
+package contractid
+
+import (
+	"errors"
+	"mobilityid/checkdigit"
+	c "mobilityid/common"
+	"strings"
+)
+
+// CanonicalKey returns a key identifying the contract of id, which is the same for all of its representations, e.g.
+// "NLTNMC00122045" for the DIN contract ID "NL-TNM-012204-5", the EMI3 contract ID "NL-TNM-C00122045-K" and the ISO
+// contract ID "NLTNMC00122045K". It is meant to be used as a map key, when deduplicating or matching contract IDs.
+//
+// The key is the compact ISO representation of the contract, without check digit: the EMI3 'C' marker is part of the
+// ISO instance, and so is the DIN instance, preceded by "C0" and followed by the DIN check digit (computed, if
+// missing). It is "" for a nil or zero id.
+func CanonicalKey(id Reader) string {
+	if id == nil || id.IsZero() {
+		return ""
+	}
+
+	return strings.ToUpper(id.CountryCode()+id.PartyCode()) + keyOf(id).String()
+}
+
+// instanceKey is the instance value of the canonical key of a contract ID (see CanonicalKey), made of parts so that
+// it can be compared without being built
+type instanceKey struct {
+	prefix     string
+	value      string
+	checkDigit byte // the DIN check digit, or 0
+}
+
+func keyOf[T Reader](id T) instanceKey {
+	switch id.Format() {
+	case c.FormatEmi3:
+		return instanceKey{prefix: "C", value: id.InstanceValue()}
+	case c.FormatDin:
+		checkDigit := byte(id.CheckDigit())
+		if !id.HasCheckDigit() {
+			digest := checkdigit.Din.NewDigest()
+			for _, field := range [...]string{id.CountryCode(), id.PartyCode(), id.InstanceValue()} {
+				for i := 0; i < len(field); i++ {
+					_ = digest.WriteByte(toUpper(field[i]))
+				}
+			}
+
+			// the DIN algorithm accepts any input
+			sum, _ := digest.Sum()
+			checkDigit = byte(sum)
+		}
+
+		return instanceKey{prefix: "C0", value: id.InstanceValue(), checkDigit: checkDigit}
+	default:
+		return instanceKey{value: id.InstanceValue()}
+	}
+}
+
+func (k instanceKey) len() int {
+	if k.checkDigit == 0 {
+		return len(k.prefix) + len(k.value)
+	}
+
+	return len(k.prefix) + len(k.value) + 1
+}
+
+// at returns the i-th character of the key, in upper case
+func (k instanceKey) at(i int) byte {
+	switch {
+	case i < len(k.prefix):
+		return k.prefix[i]
+	case i < len(k.prefix)+len(k.value):
+		return toUpper(k.value[i-len(k.prefix)])
+	default:
+		return toUpper(k.checkDigit)
+	}
+}
+
+func (k instanceKey) String() string {
+	var sb strings.Builder
+	for i := 0; i < k.len(); i++ {
+		sb.WriteByte(k.at(i))
+	}
+
+	return sb.String()
+}
+
+// compareKeys compares a and b like strings.Compare, ignoring case
+func compareKeys(a, b instanceKey) int {
+	for i := 0; i < a.len() && i < b.len(); i++ {
+		if x, y := a.at(i), b.at(i); x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	switch {
+	case a.len() < b.len():
+		return -1
+	case a.len() > b.len():
+		return 1
+	default:
+		return 0
+	}
+}
+
+func toUpper(b byte) byte {
+	if b >= 'a' && b <= 'z' {
+		return b - ('a' - 'A')
+	}
+
+	return b
+}
+
+// Equivalent returns true if a and b represent the same contract, whatever their format (see CanonicalKey)
+func Equivalent(a, b Reader) bool {
+	keyA := CanonicalKey(a)
+
+	return keyA != "" && keyA == CanonicalKey(b)
+}
+
+// ParseCanonicalKey parses input like Parse, and returns the canonical key of the contract ID it represents. Inputs
+// which are valid in more than one format are not ambiguous, as long as all readings represent the same contract.
+func ParseCanonicalKey(input string) (string, error) {
+	id, err := Parse(input)
+	if err == nil {
+		return CanonicalKey(id), nil
+	}
+
+	var ambiguous *AmbiguousError
+	if !errors.As(err, &ambiguous) {
+		return "", err
+	}
+
+	key := CanonicalKey(ambiguous.Candidates[0])
+	for _, candidate := range ambiguous.Candidates[1:] {
+		if CanonicalKey(candidate) != key {
+			return "", err
+		}
+	}
+
+	return key, nil
+}
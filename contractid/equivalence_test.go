@@ -0,0 +1,92 @@
+package contractid_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	c "mobilityid/common"
+	"mobilityid/contractid"
+	"mobilityid/contractid/din"
+	"mobilityid/contractid/emi3"
+	"mobilityid/contractid/iso"
+	"mobilityid/grammar"
+	"testing"
+)
+
+func TestCanonicalKey(t *testing.T) {
+	dinId, _ := din.Parse("NL-TNM-012204-5")
+	dinIdNoCheckDigit, _ := din.Parse("nl*tnm*012204")
+	emi3Id, _ := emi3.Parse("NL-TNM-C00122045-K")
+	emi3IdNoCheckDigit, _ := emi3.Parse("NLTNMC00122045")
+	isoId, _ := iso.Parse("NLTNMC00122045K")
+
+	for _, id := range []contractid.Reader{dinId, dinIdNoCheckDigit, emi3Id, emi3IdNoCheckDigit, isoId} {
+		assert.Equal(t, "NLTNMC00122045", contractid.CanonicalKey(id), id.String())
+	}
+
+	assert.Equal(t, "", contractid.CanonicalKey(nil))
+	assert.Equal(t, "", contractid.CanonicalKey(iso.ContractId{}))
+}
+
+func TestEquivalent(t *testing.T) {
+	dinId, _ := din.Parse("NL-TNM-012204-5")
+	emi3Id, _ := emi3.Parse("NL-TNM-C00122045-K")
+	isoId, _ := iso.Parse("NLTNMC00122045K")
+	otherIsoId, _ := iso.Parse("NL-TNM-001234567-X")
+	otherDinId, _ := din.Parse("NL-TNM-012205")
+
+	assert.True(t, contractid.Equivalent(dinId, emi3Id))
+	assert.True(t, contractid.Equivalent(emi3Id, isoId))
+	assert.True(t, contractid.Equivalent(isoId, dinId))
+	assert.True(t, contractid.Equivalent(isoId, isoId))
+
+	assert.False(t, contractid.Equivalent(isoId, otherIsoId))
+	assert.False(t, contractid.Equivalent(dinId, otherDinId))
+	assert.False(t, contractid.Equivalent(iso.ContractId{}, emi3.ContractId{}))
+	assert.False(t, contractid.Equivalent(nil, nil))
+}
+
+func TestEquality_CountryPolicy(t *testing.T) {
+	kosovo := grammar.ParseOptions{Countries: &c.CountryPolicy{UserAssigned: []c.Country{c.Kosovo}}}
+	kosovoInEEA := grammar.ParseOptions{
+		Countries: &c.CountryPolicy{Allowed: append([]string{"XK"}, c.EEA...), UserAssigned: []c.Country{c.Kosovo}},
+	}
+	test := grammar.ParseOptions{Countries: &c.CountryPolicy{Test: []string{"XK"}}}
+
+	cases := []struct {
+		name     string
+		a        string
+		aOpts    grammar.ParseOptions
+		b        string
+		bOpts    grammar.ParseOptions
+		expected bool
+	}{
+		{"same policy", "XK-TNM-001234567", kosovo, "XK-TNM-001234567", kosovo, true},
+		{"policies resolving to the same country", "XK-TNM-001234567", kosovo, "XKTNM001234567", kosovoInEEA, true},
+		{"policies resolving to different countries", "XK-TNM-001234567", kosovo, "XK-TNM-001234567", test, false},
+		{"country known without policy", "NL-TNM-001234567", kosovo, "NL-TNM-001234567", grammar.ParseOptions{}, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			a, err := iso.ParseWith(tc.a, tc.aOpts)
+			assert.Nil(t, err)
+			b, err := iso.ParseWith(tc.b, tc.bOpts)
+			assert.Nil(t, err)
+
+			assert.Equal(t, tc.expected, a == b)
+			assert.True(t, contractid.Equivalent(a, b))
+			assert.Equal(t, contractid.CanonicalKey(a), contractid.CanonicalKey(b))
+		})
+	}
+}
+
+func TestParseCanonicalKey(t *testing.T) {
+	for _, input := range []string{"NL-TNM-012204-5", "NL-TNM-C00122045-K", "NLTNMC00122045K"} {
+		key, err := contractid.ParseCanonicalKey(input)
+
+		assert.Nil(t, err, input)
+		assert.Equal(t, "NLTNMC00122045", key, input)
+	}
+
+	_, err := contractid.ParseCanonicalKey("XYZ")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,12 @@
+package contractid
+
+// WithoutFormats runs f as if no format was registered
+func WithoutFormats(f func()) {
+	registered := formats
+	defer func() {
+		formats = registered
+	}()
+
+	formats = nil
+	f()
+}
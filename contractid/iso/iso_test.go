@@ -1,8 +1,13 @@
 package iso
 
 import (
+	"errors"
 	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"mobilityid/checkdigit"
+	c "mobilityid/common"
 	"mobilityid/contractid"
+	"mobilityid/grammar"
 	"testing"
 )
 
@@ -13,7 +18,7 @@ var (
 		InstanceValue: "001234567",
 		CheckDigit:    'X',
 	}
-	expectedId                = &ContractId{contractid.Id(input.CountryCode, input.PartyCode, input.InstanceValue, input.CheckDigit)}
+	expectedId                = ContractId{contractid.NewId(input.CountryCode, input.PartyCode, input.InstanceValue, input.CheckDigit)}
 	assertValidId             = contractid.NewAssertValidId(input.CountryCode, input.PartyCode, input.InstanceValue, input.CheckDigit)
 	assertValidIdNoCheckDigit = contractid.NewAssertValidIdNoCheckDigit(input.CountryCode, input.PartyCode, input.InstanceValue)
 )
@@ -207,3 +212,290 @@ func TestNewContractIdNoCheckDigit(t *testing.T) {
 		})
 	}
 }
+
+func TestContractId_IsZero(t *testing.T) {
+	t.Run("returns true for the zero value, which can be used without panicking", func(t *testing.T) {
+		id := ContractId{}
+
+		assert.True(t, id.IsZero())
+		assert.Equal(t, "", id.String())
+		assert.Equal(t, "", id.CompactStringNoCheckDigit())
+	})
+
+	t.Run("returns false for a non-empty ID", func(t *testing.T) {
+		assert.False(t, expectedId.IsZero())
+	})
+}
+
+func TestContractId_Equality(t *testing.T) {
+	t.Run("compares IDs by value, so that they can be used as map keys", func(t *testing.T) {
+		parsed, err := Parse("nl-tnm-001234567-x")
+		assert.Nil(t, err)
+
+		created, err := NewContractId(input.CountryCode, input.PartyCode, input.InstanceValue, input.CheckDigit)
+		assert.Nil(t, err)
+
+		assert.True(t, parsed == created)
+		assert.True(t, map[ContractId]bool{parsed: true}[created])
+	})
+}
+
+func TestContractId_Validate(t *testing.T) {
+	cases := []struct {
+		name        string
+		id          ContractId
+		expectedErr bool
+	}{
+		{
+			name: "accepts a valid ID",
+			id:   expectedId,
+		},
+		{
+			name: "accepts a valid ID without check digit",
+			id:   ContractId{contractid.NewId(input.CountryCode, input.PartyCode, input.InstanceValue, 0)},
+		},
+		{
+			name:        "rejects the zero value",
+			id:          ContractId{},
+			expectedErr: true,
+		},
+		{
+			name:        "rejects an ID with lower case fields",
+			id:          ContractId{contractid.NewId("nl", "tnm", input.InstanceValue, input.CheckDigit)},
+			expectedErr: true,
+		},
+		{
+			name:        "rejects an ID with an invalid check digit",
+			id:          ContractId{contractid.NewId(input.CountryCode, input.PartyCode, input.InstanceValue, 'A')},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.id.Validate()
+
+			assert.Equal(t, test.expectedErr, err != nil)
+		})
+	}
+}
+
+func TestContractId_CheckDigit(t *testing.T) {
+	t.Run("prints a check digit equal to '0'", func(t *testing.T) {
+		id, err := NewContractIdNoCheckDigit("DE", "8AA", "001234567")
+		assert.Nil(t, err)
+
+		assert.True(t, id.HasCheckDigit())
+		assert.Equal(t, "DE-8AA-001234567-0", id.String())
+		assert.Equal(t, "DE8AA0012345670", id.CompactString())
+		assert.Equal(t, "DE8AA001234567", id.CompactStringNoCheckDigit())
+	})
+
+	t.Run("prints no check digit if absent", func(t *testing.T) {
+		id, err := Parse("DE-8AA-001234567")
+		assert.Nil(t, err)
+
+		assert.False(t, id.HasCheckDigit())
+		assert.Equal(t, "DE-8AA-001234567", id.String())
+		assert.Equal(t, "DE8AA001234567", id.CompactString())
+		assert.Equal(t, "DE8AA001234567", id.CompactStringNoCheckDigit())
+	})
+}
+
+func TestContractId_WithComputedCheckDigit(t *testing.T) {
+	t.Run("computes the missing check digit", func(t *testing.T) {
+		id, err := Parse("NL-TNM-001234567")
+		assert.Nil(t, err)
+
+		withCheckDigit, err := id.WithComputedCheckDigit()
+
+		assert.Nil(t, err)
+		assert.Equal(t, expectedId, withCheckDigit)
+	})
+
+	t.Run("returns an error if the ID is not valid", func(t *testing.T) {
+		_, err := ContractId{}.WithComputedCheckDigit()
+
+		assert.NotNil(t, err)
+	})
+}
+
+func TestContractId_Identifier(t *testing.T) {
+	t.Run("implements the Identifier interface", func(t *testing.T) {
+		var id c.Identifier = expectedId
+
+		assert.Equal(t, c.KindContract, id.Kind())
+		assert.Equal(t, c.FormatIso, id.Format())
+		assert.Equal(t, expectedId.PartyId(), id.PartyId())
+		assert.Equal(t, "NLD", id.Country().Alpha3)
+	})
+}
+
+func TestParse_Errors(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		expected []error
+	}{
+		{
+			name:     "returns ErrInvalidFormat for a string not matching the format",
+			input:    "XYZ",
+			expected: []error{c.ErrInvalidFormat},
+		},
+		{
+			name:     "returns ErrInvalidCountryCode for an unknown country",
+			input:    "ZZ-TNM-001234567-X",
+			expected: []error{c.ErrInvalidCountryCode, c.ErrUnknownCountry},
+		},
+		{
+			name:     "returns ErrInvalidCheckDigit for a wrong check digit",
+			input:    "NL-TNM-001234567-A",
+			expected: []error{c.ErrInvalidCheckDigit},
+		},
+		{
+			name:     "returns the error of a segment of the wrong length",
+			input:    "NL-TNM-00123456-X",
+			expected: []error{c.ErrInvalidFormat, c.ErrInvalidInstance, c.ErrWrongLength},
+		},
+		{
+			name:     "returns the error of a segment with invalid characters",
+			input:    "NL-TN#-001234567-X",
+			expected: []error{c.ErrInvalidFormat, c.ErrInvalidPartyCode, c.ErrInvalidCharacter},
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Parse(test.input)
+
+			for _, expected := range test.expected {
+				assert.True(t, errors.Is(err, expected))
+			}
+		})
+	}
+
+	t.Run("reports the expected check digit", func(t *testing.T) {
+		_, err := Parse("NL-TNM-001234567-A")
+
+		var checkDigitErr *c.CheckDigitError
+		assert.True(t, errors.As(err, &checkDigitErr))
+		assert.Equal(t, 'X', checkDigitErr.Expected)
+		assert.Equal(t, 'A', checkDigitErr.Actual)
+	})
+}
+
+func TestNewContractIdNoCheckDigit_Errors(t *testing.T) {
+	t.Run("reports every invalid field at once", func(t *testing.T) {
+		_, err := NewContractIdNoCheckDigit("ZZ", "TNMA", "001234567890")
+
+		var errs c.ValidationErrors
+		assert.True(t, errors.As(err, &errs))
+		assert.Equal(t, []c.Field{c.FieldCountryCode, c.FieldPartyCode, c.FieldInstance}, errs.Fields())
+	})
+}
+
+func TestGenerate(t *testing.T) {
+	t.Run("generates valid contract IDs, which can be parsed back", func(t *testing.T) {
+		r := rand.New(rand.NewSource(1))
+
+		for i := 0; i < 100; i++ {
+			id := Generate(r)
+			assert.Nil(t, id.Validate())
+
+			parsed, err := Parse(id.String())
+			assert.Nil(t, err)
+			assert.Equal(t, id, parsed)
+		}
+	})
+}
+
+func TestParseBytes(t *testing.T) {
+	for _, s := range []string{expectedId.String(), expectedId.CompactString()} {
+		id, err := ParseBytes([]byte(s))
+
+		assert.Nil(t, err)
+		assert.Equal(t, expectedId, id)
+		assert.Nil(t, Check(s))
+		assert.Nil(t, CheckBytes([]byte(s)))
+	}
+
+	_, expectedErr := Parse("invalid")
+	_, err := ParseBytes([]byte("invalid"))
+
+	assert.NotNil(t, err)
+	assert.Equal(t, expectedErr, err)
+	assert.Equal(t, expectedErr, Check("invalid"))
+	assert.Equal(t, expectedErr, CheckBytes([]byte("invalid")))
+}
+
+func TestParseWith_CountryPolicy(t *testing.T) {
+	policy := &c.CountryPolicy{Allowed: c.EEA, Aliases: c.Aliases, Test: []string{"ZZ"}}
+	opts := grammar.ParseOptions{Countries: policy}
+
+	t.Run("accepts allowed countries", func(t *testing.T) {
+		id, err := ParseWith("NL-TNM-001234567-X", opts)
+
+		assert.Nil(t, err)
+		assert.Equal(t, expectedId, id)
+	})
+
+	t.Run("rejects countries which are not allowed", func(t *testing.T) {
+		_, err := ParseWith("US-TNM-001234567", opts)
+
+		assert.True(t, errors.Is(err, c.ErrCountryNotAllowed))
+	})
+
+	t.Run("accepts aliases as they are, verifying the check digit computed from them", func(t *testing.T) {
+		policy := &c.CountryPolicy{Allowed: []string{"GB"}, Aliases: c.Aliases}
+		checkDigit, _ := checkdigit.Iso.Compute("UKTNM001234567")
+
+		id, err := ParseWith("UK-TNM-001234567-"+string(checkDigit), grammar.ParseOptions{Countries: policy})
+		assert.Nil(t, err)
+		assert.Equal(t, "UK", id.CountryCode())
+
+		country, err := policy.Resolve(id.CountryCode())
+		assert.Nil(t, err)
+		assert.Equal(t, "GB", country.Alpha2)
+	})
+
+	t.Run("accepts test codes", func(t *testing.T) {
+		id, err := ParseWith("ZZ-TNM-001234567", opts)
+
+		assert.Nil(t, err)
+		assert.Nil(t, id.Validate())
+		assert.Equal(t, "ZZ", id.Country().Alpha2)
+	})
+
+	t.Run("keeps the country resolved by the policy", func(t *testing.T) {
+		policy := &c.CountryPolicy{Allowed: []string{"GB", "XK"}, UserAssigned: []c.Country{c.Kosovo}, Aliases: c.Aliases}
+
+		for _, tc := range []struct {
+			countryCode string
+			expected    c.Country
+		}{
+			{"XK", c.Kosovo},
+			{"UK", c.CountryOf("GB")},
+		} {
+			id, err := ParseWith(tc.countryCode+"-TNM-001234567", grammar.ParseOptions{Countries: policy})
+			assert.Nil(t, err, tc.countryCode)
+			assert.Equal(t, tc.expected, id.Country(), tc.countryCode)
+			assert.Nil(t, id.Validate(), tc.countryCode)
+
+			computed, err := id.WithComputedCheckDigit()
+			assert.Nil(t, err, tc.countryCode)
+			assert.Equal(t, tc.expected, computed.Country(), tc.countryCode)
+			assert.Nil(t, computed.Validate(), tc.countryCode)
+
+			_, err = Parse(computed.String())
+			assert.True(t, errors.Is(err, c.ErrUnknownCountry), tc.countryCode)
+		}
+	})
+
+	t.Run("doesn't keep countries which don't need the policy", func(t *testing.T) {
+		id, err := ParseWith("NL-TNM-001234567-X", opts)
+
+		assert.Nil(t, err)
+		assert.Equal(t, expectedId, id)
+		assert.Equal(t, "Netherlands", id.Country().Name)
+	})
+}
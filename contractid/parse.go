@@ -0,0 +1,98 @@
+package contractid
+
+import (
+	"fmt"
+	c "mobilityid/common"
+	"mobilityid/grammar"
+	"sort"
+	"strings"
+)
+
+// ParseFunc parses an input string into a contract ID of a given format, as strictly as required by opts
+type ParseFunc func(input string, opts grammar.ParseOptions) (Reader, error)
+
+type registeredFormat struct {
+	format c.Format
+	parse  ParseFunc
+}
+
+var formats []registeredFormat
+
+// RegisterFormat makes a contract ID format available to Parse. It is called by the format packages at init time,
+// so a format is only detected if its package is imported, e.g.
+//
+//	import _ "mobilityid/contractid/iso"
+//
+// or all of them at once, with
+//
+//	import _ "mobilityid/formats"
+func RegisterFormat(format c.Format, parse ParseFunc) {
+	for i, f := range formats {
+		if f.format == format {
+			formats[i].parse = parse
+			return
+		}
+	}
+
+	formats = append(formats, registeredFormat{format: format, parse: parse})
+	sort.Slice(formats, func(i, j int) bool {
+		return formats[i].format < formats[j].format
+	})
+}
+
+// AmbiguousError is returned by Parse when an input is a valid contract ID in more than one format
+type AmbiguousError struct {
+	Input      string
+	Candidates []Reader
+}
+
+func (e *AmbiguousError) Error() string {
+	readings := make([]string, 0, len(e.Candidates))
+	for _, candidate := range e.Candidates {
+		readings = append(readings, fmt.Sprintf("%v %v", candidate.Format(), candidate.String()))
+	}
+
+	return fmt.Sprintf("ambiguous contract ID: %v (%s)", e.Input, strings.Join(readings, ", "))
+}
+
+// Parse parses the input string into a contract ID, trying all registered formats; its format is returned by Format().
+//
+// It returns an *AmbiguousError, listing every reading, if the input is valid in more than one format (e.g. a 14
+// characters ISO ID whose instance starts with 'C' is also a valid EMI3 ID), or a *common.NoMatchError, explaining why
+// each format was rejected, if the input is not valid in any format; it matches common.ErrNoFormats if no format is
+// registered (see RegisterFormat).
+func Parse(input string) (Reader, error) {
+	return Parser{}.Parse(input)
+}
+
+// Parser parses contract IDs like Parse, as strictly as required by its Options, e.g.
+//
+//	contractid.Parser{Options: grammar.Strict}.Parse("NL-TNM-C00122045-K")
+type Parser struct {
+	Options grammar.ParseOptions
+}
+
+// Parse parses the input string into a contract ID, trying all registered formats (see contractid.Parse)
+func (p Parser) Parse(input string) (Reader, error) {
+	var candidates []Reader
+	var rejections []c.Rejection
+
+	for _, f := range formats {
+		id, err := f.parse(input, p.Options)
+		if err != nil {
+			rejections = append(rejections, c.Rejection{Format: f.format, Err: err})
+			continue
+		}
+
+		candidates = append(candidates, id)
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil, &c.NoMatchError{Kind: c.KindContract, Input: input, Rejections: rejections}
+	case 1:
+		return candidates[0], nil
+	default:
+		return nil, &AmbiguousError{Input: input, Candidates: candidates}
+	}
+}
@@ -0,0 +1,111 @@
+package contractid_test
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	c "mobilityid/common"
+	"mobilityid/contractid"
+	_ "mobilityid/contractid/din"
+	_ "mobilityid/contractid/emi3"
+	_ "mobilityid/contractid/iso"
+	"mobilityid/grammar"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		name           string
+		input          string
+		expectedFormat c.Format
+		expectedString string
+	}{
+		{
+			name:           "detects an ISO contract ID",
+			input:          "NL-TNM-001234567-X",
+			expectedFormat: c.FormatIso,
+			expectedString: "NL-TNM-001234567-X",
+		},
+		{
+			name:           "detects a DIN contract ID",
+			input:          "NL*TNM*012204*5",
+			expectedFormat: c.FormatDin,
+			expectedString: "NL-TNM-012204-5",
+		},
+		{
+			name:           "detects a DIN contract ID without check digit",
+			input:          "NLTNM012204",
+			expectedFormat: c.FormatDin,
+			expectedString: "NL-TNM-012204",
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			id, err := contractid.Parse(test.input)
+
+			assert.Nil(t, err)
+			assert.Equal(t, test.expectedFormat, id.Format())
+			assert.Equal(t, test.expectedString, id.String())
+		})
+	}
+
+	t.Run("returns every reading of an input valid in more than one format", func(t *testing.T) {
+		_, err := contractid.Parse("NL-TNM-C00122045-K")
+
+		var ambiguous *contractid.AmbiguousError
+		assert.True(t, errors.As(err, &ambiguous))
+		assert.Len(t, ambiguous.Candidates, 2)
+		assert.Equal(t, c.FormatIso, ambiguous.Candidates[0].Format())
+		assert.Equal(t, "C00122045", ambiguous.Candidates[0].InstanceValue())
+		assert.Equal(t, c.FormatEmi3, ambiguous.Candidates[1].Format())
+		assert.Equal(t, "00122045", ambiguous.Candidates[1].InstanceValue())
+	})
+
+	t.Run("explains why each format was rejected if none matches", func(t *testing.T) {
+		_, err := contractid.Parse("XYZ")
+
+		var noMatch *c.NoMatchError
+		assert.True(t, errors.As(err, &noMatch))
+		assert.Equal(t, c.KindContract, noMatch.Kind)
+		assert.Len(t, noMatch.Rejections, 3)
+		for _, rejection := range noMatch.Rejections {
+			assert.NotNil(t, rejection.Err)
+		}
+		assert.False(t, errors.Is(err, c.ErrNoFormats))
+	})
+
+	t.Run("reports that no format is registered", func(t *testing.T) {
+		contractid.WithoutFormats(func() {
+			_, err := contractid.Parse("NL-TNM-C00122045-K")
+			assert.True(t, errors.Is(err, c.ErrNoFormats))
+			assert.Contains(t, err.Error(), "mobilityid/formats")
+
+			_, err = contractid.ParseCanonicalKey("NL-TNM-C00122045-K")
+			assert.True(t, errors.Is(err, c.ErrNoFormats))
+		})
+	})
+}
+
+func TestParser_Parse(t *testing.T) {
+	t.Run("accepts forgiving inputs when lenient", func(t *testing.T) {
+		id, err := contractid.Parser{Options: grammar.Lenient}.Parse(" nl*tnm*012204 ")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "NL-TNM-012204", id.String())
+	})
+
+	t.Run("only accepts canonical inputs when strict", func(t *testing.T) {
+		strict := contractid.Parser{Options: grammar.Strict}
+
+		id, err := strict.Parse("NL-TNM-012204-5")
+		assert.Nil(t, err)
+		assert.Equal(t, c.FormatDin, id.Format())
+
+		for _, input := range []string{"NL*TNM*012204*5", "NL-TNM-012204", "nl-tnm-012204-5", "NLTNM0122045"} {
+			_, err := strict.Parse(input)
+
+			var noMatch *c.NoMatchError
+			assert.True(t, errors.As(err, &noMatch), input)
+		}
+	})
+}
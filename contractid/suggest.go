@@ -0,0 +1,22 @@
+package contractid
+
+import "mobilityid/grammar"
+
+// Suggestion is the correction of a single typo, turning an invalid input into a valid contract ID
+type Suggestion struct {
+	grammar.Suggestion
+	Id Reader
+}
+
+// Suggest lists the corrections of input suggested by g (see grammar.Grammar.Suggest), most likely first, along with
+// the IDs they are parsed into by parse. It is meant to be called by format packages, e.g. iso.Suggest.
+func Suggest(g *grammar.Grammar, input string, parse ParseFunc) []Suggestion {
+	var result []Suggestion
+	for _, s := range g.Suggest(input) {
+		if id, err := parse(s.Input, grammar.ParseOptions{}); err == nil {
+			result = append(result, Suggestion{Suggestion: s, Id: id})
+		}
+	}
+
+	return result
+}
@@ -22,6 +22,7 @@ func NewAssertValidIdNoCheckDigit(countryCode, partyCode, instance string) func(
 		assert.Equal(t, countryCode, reader.CountryCode())
 		assert.Equal(t, partyCode, reader.PartyCode())
 		assert.Equal(t, instance, reader.InstanceValue())
+		assert.False(t, reader.HasCheckDigit())
 	}
 }
 
@@ -31,6 +32,7 @@ func NewAssertValidId(countryCode, partyCode, instance string, checkDigit rune)
 		assert.Equal(t, countryCode, reader.CountryCode())
 		assert.Equal(t, partyCode, reader.PartyCode())
 		assert.Equal(t, instance, reader.InstanceValue())
+		assert.True(t, reader.HasCheckDigit())
 		assert.Equal(t, checkDigit, reader.CheckDigit())
 	}
 }
@@ -0,0 +1,88 @@
+package evseid
+
+import (
+	c "mobilityid/common"
+)
+
+// Compare defines a total order of EVSE IDs, whatever their format: by country code, operator code and power outlet
+// ID, ignoring case as well as the separators within power outlet IDs, then by power outlet ID including separators,
+// so that e.g. "NL*TNM*E03*0" and "NL*TNM*E030" are adjacent but distinct, and finally by format. It returns -1, 0
+// or +1, so it can be passed to slices.SortFunc or slices.BinarySearchFunc, e.g.
+//
+//	slices.SortFunc(ids, evseid.Compare[iso.EvseId])
+func Compare[T Reader](a, b T) int {
+	if r := comparePowerOutlet(a, b.CountryCode(), b.OperatorCode(), b.PowerOutletId()); r != 0 {
+		return r
+	}
+
+	if r := c.CompareFold(a.PowerOutletId(), b.PowerOutletId(), ""); r != 0 {
+		return r
+	}
+
+	switch {
+	case a.Format() < b.Format():
+		return -1
+	case a.Format() > b.Format():
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Equal returns true if a and b identify the same EVSE in the same format, whatever their spelling: case and the
+// optional separators after country and operator codes are ignored, but not the separators within power outlet IDs,
+// so that e.g. "NLTNME03*0" equals "NL*TNM*E03*0" but not "NL*TNM*E030". Equal IDs have the same Canonical form.
+func Equal(a, b Reader) bool {
+	return Compare(a, b) == 0
+}
+
+// compareOperator compares the operator of id with the one identified by countryCode and operatorCode, ignoring case
+func compareOperator(id Reader, countryCode, operatorCode string) int {
+	if r := c.CompareFold(id.CountryCode(), countryCode, ""); r != 0 {
+		return r
+	}
+
+	return c.CompareFold(id.OperatorCode(), operatorCode, "")
+}
+
+// comparePowerOutlet compares the operator and power outlet ID of id with the given ones, ignoring case and the
+// separators within power outlet IDs
+func comparePowerOutlet(id Reader, countryCode, operatorCode, powerOutletId string) int {
+	if r := compareOperator(id, countryCode, operatorCode); r != 0 {
+		return r
+	}
+
+	return c.CompareFold(id.PowerOutletId(), powerOutletId, "*")
+}
+
+// Set is a set of EVSE IDs, sorted by Compare; it must be created with NewSet.
+type Set[T Reader] struct {
+	c.SortedSet[T]
+}
+
+// NewSet returns a set of ids
+func NewSet[T Reader](ids ...T) *Set[T] {
+	return &Set[T]{c.NewSortedSet(Compare[T], ids...)}
+}
+
+// Operator returns the IDs of the operator identified by countryCode and operatorCode, in order
+func (s *Set[T]) Operator(countryCode, operatorCode string) []T {
+	bound := func(id T) int {
+		return compareOperator(id, countryCode, operatorCode)
+	}
+
+	return s.Between(bound, bound)
+}
+
+// Range returns the IDs of the operator identified by countryCode and operatorCode whose power outlet ID is between
+// from and to, both included and compared without separators, in order.
+func (s *Set[T]) Range(countryCode, operatorCode, from, to string) []T {
+	return s.Between(
+		func(id T) int {
+			return comparePowerOutlet(id, countryCode, operatorCode, from)
+		},
+		func(id T) int {
+			return comparePowerOutlet(id, countryCode, operatorCode, to)
+		},
+	)
+}
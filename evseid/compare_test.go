@@ -0,0 +1,100 @@
+package evseid_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	c "mobilityid/common"
+	"mobilityid/evseid"
+	"mobilityid/evseid/iso"
+	"mobilityid/grammar"
+	"testing"
+)
+
+func mustParseIso(input string) iso.EvseId {
+	id, err := iso.Parse(input)
+	if err != nil {
+		panic(err)
+	}
+
+	return id
+}
+
+func TestCompare(t *testing.T) {
+	assert.Equal(t, 0, evseid.Compare(mustParseIso("NL*TNM*E03*0"), mustParseIso("nl*tnm*e03*0")))
+	assert.Equal(t, -1, evseid.Compare(mustParseIso("NL*TNM*E03*0"), mustParseIso("NL*TNM*E030")))
+	assert.Equal(t, -1, evseid.Compare(mustParseIso("NL*TNM*E030"), mustParseIso("NL*TNM*E03*1")))
+	assert.Equal(t, 1, evseid.Compare(mustParseIso("NL*TNM*E1"), mustParseIso("NL*TNM*E03*1")))
+	assert.Equal(t, -1, evseid.Compare(mustParseIso("DE*TNM*E1"), mustParseIso("NL*ABC*E1")))
+}
+
+func TestEqual(t *testing.T) {
+	cases := []struct {
+		name     string
+		a        string
+		b        string
+		expected bool
+	}{
+		{
+			name:     "ignores case",
+			a:        "NL*TNM*E03*0",
+			b:        "nl*tnm*e03*0",
+			expected: true,
+		},
+		{
+			name:     "ignores optional separators after country and operator codes",
+			a:        "NL*TNM*E03*0",
+			b:        "NLTNME03*0",
+			expected: true,
+		},
+		{
+			name:     "doesn't ignore separators within power outlet IDs",
+			a:        "NL*TNM*E03*0",
+			b:        "NL*TNM*E030",
+			expected: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			a, b := mustParseIso(tc.a), mustParseIso(tc.b)
+
+			assert.Equal(t, tc.expected, evseid.Equal(a, b))
+			assert.Equal(t, tc.expected, a.Canonical() == b.Canonical())
+		})
+	}
+}
+
+func TestEqual_CountryPolicy(t *testing.T) {
+	kosovo := grammar.ParseOptions{Countries: &c.CountryPolicy{UserAssigned: []c.Country{c.Kosovo}}}
+	test := grammar.ParseOptions{Countries: &c.CountryPolicy{Test: []string{"XK"}}}
+
+	asKosovo, err := iso.ParseWith("XK*TNM*E1", kosovo)
+	assert.Nil(t, err)
+	asKosovoAgain, err := iso.ParseWith("XKTNME1", kosovo)
+	assert.Nil(t, err)
+	asTest, err := iso.ParseWith("XK*TNM*E1", test)
+	assert.Nil(t, err)
+
+	assert.True(t, asKosovo == asKosovoAgain)
+	assert.False(t, asKosovo == asTest)
+	assert.True(t, evseid.Equal(asKosovo, asTest))
+	assert.Equal(t, asKosovo.Canonical(), asTest.Canonical())
+
+	withPolicy, _ := iso.ParseWith("NL*TNM*E1", kosovo)
+	assert.True(t, withPolicy == mustParseIso("NL*TNM*E1"))
+}
+
+func TestSet(t *testing.T) {
+	s := evseid.NewSet(
+		mustParseIso("NL*TNM*E030"),
+		mustParseIso("NL*TNM*E03*0"),
+		mustParseIso("NL*TNM*E04"),
+		mustParseIso("NL*ABC*E03"),
+	)
+
+	assert.Equal(t, 4, s.Len())
+	assert.Len(t, s.Operator("NL", "TNM"), 3)
+	assert.Equal(t, []iso.EvseId{
+		mustParseIso("NL*TNM*E03*0"),
+		mustParseIso("NL*TNM*E030"),
+	}, s.Range("NL", "TNM", "03", "03*9"))
+}
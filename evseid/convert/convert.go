@@ -0,0 +1,107 @@
+// Package convert converts EVSE IDs between the DIN and ISO formats.
+//
+// Country codes are converted with the table of ITU calling codes of common.CallingCode, but operator codes can't be
+// derived from one another: they are converted with an OperatorMapping, e.g. an OperatorTable. The mapping also tells
+// the country of DIN EVSE IDs whose calling code is shared by many countries, like "+1".
+package convert
+
+import (
+	"errors"
+	"fmt"
+	c "mobilityid/common"
+	"mobilityid/evseid/din"
+	"mobilityid/evseid/iso"
+	"strings"
+)
+
+// Reasons why a conversion isn't possible, to be matched with errors.Is against the errors returned by conversions
+var (
+	// ErrUnknownOperator is returned when the operator mapping doesn't know the operator of the ID
+	ErrUnknownOperator = errors.New("unknown operator")
+	// ErrCountryMismatch is returned when an ISO country doesn't use the calling code of the DIN operator it is mapped to
+	ErrCountryMismatch = errors.New("country doesn't match calling code")
+	// ErrAmbiguousCallingCode is returned when the country of an ISO operator is left out, but its calling code is
+	// used by many countries
+	ErrAmbiguousCallingCode = errors.New("calling code is shared by many countries")
+	// ErrDuplicateOperator is returned when many DIN operators are mapped to the same ISO operator
+	ErrDuplicateOperator = errors.New("duplicate operator")
+	// ErrNotNumeric is returned when converting to DIN a power outlet ID which contains letters
+	ErrNotNumeric = errors.New("power outlet ID is not numeric")
+	// ErrPowerOutletIdTooLong is returned when converting to ISO a power outlet ID longer than 31 characters
+	ErrPowerOutletIdTooLong = errors.New("power outlet ID is too long")
+)
+
+// Error reports an EVSE ID which can't be converted to another format
+type Error struct {
+	Id     string
+	From   c.Format
+	To     c.Format
+	Reason error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("cannot convert %v EVSE ID %v to %v: %v", e.From, e.Id, e.To, e.Reason)
+}
+
+// Unwrap returns the reason why the conversion isn't possible
+func (e *Error) Unwrap() error {
+	return e.Reason
+}
+
+// DinToIso converts a DIN EVSE ID to its ISO equivalent, using operators to find its ISO operator, e.g.
+// "+49*810*000*438" to "DE*8EO*E000*438" if operators maps "+49*810" to "DE*8EO".
+func DinToIso(id din.EvseId, operators OperatorMapping) (iso.EvseId, error) {
+	fail := func(reason error) (iso.EvseId, error) {
+		return iso.EvseId{}, &Error{Id: id.String(), From: c.FormatDin, To: c.FormatIso, Reason: reason}
+	}
+
+	dinOperator := DinOperator{CallingCode: id.CountryCode(), OperatorCode: id.OperatorCode()}
+	isoOperator, ok := operators.IsoOperator(dinOperator)
+	if !ok {
+		return fail(fmt.Errorf("%w: %v", ErrUnknownOperator, dinOperator))
+	}
+
+	if callingCode := c.CallingCode(isoOperator.CountryCode); callingCode != dinOperator.CallingCode {
+		return fail(fmt.Errorf("%w: %v doesn't use %v", ErrCountryMismatch, isoOperator.CountryCode, dinOperator.CallingCode))
+	}
+
+	if limit := iso.Grammar.Segments[2].MaxLength; len(id.PowerOutletId()) > limit {
+		return fail(fmt.Errorf("%w: %d characters, at most %d allowed", ErrPowerOutletIdTooLong, len(id.PowerOutletId()), limit))
+	}
+
+	result, err := iso.NewEvseId(isoOperator.CountryCode, isoOperator.OperatorCode, id.PowerOutletId())
+	if err != nil {
+		return fail(err)
+	}
+
+	return result, nil
+}
+
+// IsoToDin converts an ISO EVSE ID to its DIN equivalent, using operators to find its DIN operator, e.g.
+// "DE*8EO*E000*438" to "+49*810*000*438" if operators maps "+49*810" to "DE*8EO"; its power outlet ID must be numeric.
+func IsoToDin(id iso.EvseId, operators OperatorMapping) (din.EvseId, error) {
+	fail := func(reason error) (din.EvseId, error) {
+		return din.EvseId{}, &Error{Id: id.String(), From: c.FormatIso, To: c.FormatDin, Reason: reason}
+	}
+
+	isoOperator := IsoOperator{CountryCode: id.CountryCode(), OperatorCode: id.OperatorCode()}
+	dinOperator, ok := operators.DinOperator(isoOperator)
+	if !ok {
+		return fail(fmt.Errorf("%w: %v", ErrUnknownOperator, isoOperator))
+	}
+
+	if callingCode := c.CallingCode(isoOperator.CountryCode); callingCode != dinOperator.CallingCode {
+		return fail(fmt.Errorf("%w: %v doesn't use %v", ErrCountryMismatch, isoOperator.CountryCode, dinOperator.CallingCode))
+	}
+
+	if strings.IndexFunc(id.PowerOutletId(), func(r rune) bool { return r != '*' && !c.IsDigit(r) }) >= 0 {
+		return fail(fmt.Errorf("%w: %v", ErrNotNumeric, id.PowerOutletId()))
+	}
+
+	result, err := din.NewEvseId(dinOperator.CallingCode, dinOperator.OperatorCode, id.PowerOutletId())
+	if err != nil {
+		return fail(err)
+	}
+
+	return result, nil
+}
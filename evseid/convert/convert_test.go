@@ -0,0 +1,146 @@
+package convert
+
+import (
+	"github.com/stretchr/testify/assert"
+	c "mobilityid/common"
+	"mobilityid/evseid/din"
+	"mobilityid/evseid/iso"
+	"strings"
+	"testing"
+)
+
+func operators(t *testing.T) *OperatorTable {
+	table, err := NewOperatorTable(map[string]string{
+		"+49*810":  "8EO",
+		"+31*745":  "NL*TNM",
+		"+1*123":   "US*ABC",
+		"+1*456":   "CA*DEF",
+		"+1*789":   "PR*GHI",
+		"+7*112":   "KZ*KZO",
+		"49*12345": "DE*AB7",
+	})
+	assert.Nil(t, err)
+
+	return table
+}
+
+func TestDinToIso(t *testing.T) {
+	table := operators(t)
+
+	cases := []struct {
+		input    string
+		expected string
+		err      error
+	}{
+		{input: "+49*810*000*438", expected: "DE*8EO*E000*438"},
+		{input: "+49*12345*1", expected: "DE*AB7*E1"},
+		{input: "+31*745*1234", expected: "NL*TNM*E1234"},
+		{input: "+1*123*1", expected: "US*ABC*E1"},
+		{input: "+1*456*1", expected: "CA*DEF*E1"},
+		{input: "+1*789*1", expected: "PR*GHI*E1"},
+		{input: "+7*112*1", expected: "KZ*KZO*E1"},
+		{input: "+49*811*1", err: ErrUnknownOperator},
+		{input: "+1*810*1", err: ErrUnknownOperator},
+		{input: "+49*810*" + strings.Repeat("1", 32), err: ErrPowerOutletIdTooLong},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.input, func(t *testing.T) {
+			dinId, err := din.Parse(tc.input)
+			assert.Nil(t, err)
+
+			result, err := DinToIso(dinId, table)
+
+			if tc.err != nil {
+				var convErr *Error
+				assert.ErrorAs(t, err, &convErr)
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, result.String())
+		})
+	}
+}
+
+func TestIsoToDin(t *testing.T) {
+	table := operators(t)
+
+	cases := []struct {
+		input    string
+		expected string
+		err      error
+	}{
+		{input: "DE*8EO*E000*438", expected: "+49*810*000*438"},
+		{input: "DE*AB7*E1", expected: "+49*12345*1"},
+		{input: "US*ABC*E1", expected: "+1*123*1"},
+		{input: "CA*DEF*E1", expected: "+1*456*1"},
+		{input: "KZ*KZO*E1", expected: "+7*112*1"},
+		{input: "CA*ABC*E1", err: ErrUnknownOperator},
+		{input: "DE*8EO*E1A", err: ErrNotNumeric},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.input, func(t *testing.T) {
+			isoId, err := iso.Parse(tc.input)
+			assert.Nil(t, err)
+
+			result, err := IsoToDin(isoId, table)
+
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, result.String())
+		})
+	}
+}
+
+func TestNewOperatorTable(t *testing.T) {
+	cases := []struct {
+		name      string
+		operators map[string]string
+		err       error
+	}{
+		{"shared calling code without country", map[string]string{"+1*123": "ABC"}, ErrAmbiguousCallingCode},
+		{"country not using the calling code", map[string]string{"+49*810": "NL*8EO"}, ErrCountryMismatch},
+		{"duplicate ISO operator", map[string]string{"+49*810": "DE*8EO", "+49*811": "8EO"}, ErrDuplicateOperator},
+		{"duplicate DIN operator", map[string]string{"+49*810": "DE*8EO", "49*810": "DE*ABC"}, ErrDuplicateOperator},
+		{"malformed DIN operator", map[string]string{"+49810": "DE*8EO"}, c.ErrInvalidFormat},
+		{"invalid DIN operator", map[string]string{"+49*81": "DE*8EO"}, c.ErrInvalidOperatorCode},
+		{"invalid ISO operator", map[string]string{"+49*810": "DE*8E"}, c.ErrInvalidOperatorCode},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewOperatorTable(tc.operators)
+
+			assert.ErrorIs(t, err, tc.err)
+		})
+	}
+}
+
+// mismatchedOperators is a faulty OperatorMapping, mapping a German operator to a Dutch one
+type mismatchedOperators struct{}
+
+func (mismatchedOperators) IsoOperator(DinOperator) (IsoOperator, bool) {
+	return IsoOperator{CountryCode: "NL", OperatorCode: "TNM"}, true
+}
+
+func (mismatchedOperators) DinOperator(IsoOperator) (DinOperator, bool) {
+	return DinOperator{CallingCode: "+49", OperatorCode: "810"}, true
+}
+
+func TestConversions_RejectMismatchedCountries(t *testing.T) {
+	dinId, _ := din.Parse("+49*810*1")
+	isoId, _ := iso.Parse("NL*TNM*E1")
+
+	_, err := DinToIso(dinId, mismatchedOperators{})
+	assert.ErrorIs(t, err, ErrCountryMismatch)
+
+	_, err = IsoToDin(isoId, mismatchedOperators{})
+	assert.ErrorIs(t, err, ErrCountryMismatch)
+}
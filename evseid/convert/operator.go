@@ -0,0 +1,133 @@
+package convert
+
+import (
+	"fmt"
+	c "mobilityid/common"
+	"mobilityid/evseid/din"
+	"mobilityid/evseid/iso"
+	"strings"
+)
+
+// DinOperator is the operator of DIN EVSE IDs, e.g. "+49*810"
+type DinOperator struct {
+	// CallingCode is the ITU calling code of the country, with its leading '+'
+	CallingCode  string
+	OperatorCode string
+}
+
+func (o DinOperator) String() string {
+	return o.CallingCode + "*" + o.OperatorCode
+}
+
+// IsoOperator is the operator of ISO EVSE IDs, e.g. "DE*8EO"
+type IsoOperator struct {
+	CountryCode  string
+	OperatorCode string
+}
+
+func (o IsoOperator) String() string {
+	return o.CountryCode + "*" + o.OperatorCode
+}
+
+// OperatorMapping maps the numeric operators of DIN EVSE IDs to the alphanumeric operators of ISO EVSE IDs, and back.
+// Implementations must be safe for concurrent use.
+type OperatorMapping interface {
+	// IsoOperator returns the ISO operator of the DIN operator, if known
+	IsoOperator(operator DinOperator) (IsoOperator, bool)
+	// DinOperator returns the DIN operator of the ISO operator, if known
+	DinOperator(operator IsoOperator) (DinOperator, bool)
+}
+
+// OperatorTable is an OperatorMapping listing every operator; it is immutable, hence safe for concurrent use.
+type OperatorTable struct {
+	toIso map[DinOperator]IsoOperator
+	toDin map[IsoOperator]DinOperator
+}
+
+// NewOperatorTable returns the OperatorTable of operators, mapping DIN operators (e.g. "+49*810") to ISO ones
+// (e.g. "DE*8EO"). The country code of ISO operators can be left out (e.g. "8EO") if their calling code is used by a
+// single country, which isn't the case of "+1" or "+7": mappings must be one to one, so that they can be reversed,
+// and the country of each ISO operator must use the calling code of its DIN operator. Operators are compared once
+// normalized, so "+49*810" and "49*810" are the same DIN operator.
+func NewOperatorTable(operators map[string]string) (*OperatorTable, error) {
+	table := &OperatorTable{
+		toIso: make(map[DinOperator]IsoOperator, len(operators)),
+		toDin: make(map[IsoOperator]DinOperator, len(operators)),
+	}
+
+	for dinOperator, isoOperator := range operators {
+		from, err := parseDinOperator(dinOperator)
+		if err != nil {
+			return nil, err
+		}
+
+		to, err := parseIsoOperator(isoOperator, from.CallingCode)
+		if err != nil {
+			return nil, fmt.Errorf("operator %v: %w", dinOperator, err)
+		}
+
+		if previous, ok := table.toIso[from]; ok {
+			return nil, fmt.Errorf("operator %v: %w, already mapped to %v", from, ErrDuplicateOperator, previous)
+		}
+		if previous, ok := table.toDin[to]; ok {
+			return nil, fmt.Errorf("operator %v: %w, already mapped to %v", to, ErrDuplicateOperator, previous)
+		}
+
+		table.toIso[from] = to
+		table.toDin[to] = from
+	}
+
+	return table, nil
+}
+
+// IsoOperator returns the ISO operator of the DIN operator, if known
+func (t *OperatorTable) IsoOperator(operator DinOperator) (IsoOperator, bool) {
+	result, ok := t.toIso[operator]
+	return result, ok
+}
+
+// DinOperator returns the DIN operator of the ISO operator, if known
+func (t *OperatorTable) DinOperator(operator IsoOperator) (DinOperator, bool) {
+	result, ok := t.toDin[operator]
+	return result, ok
+}
+
+// parseDinOperator parses operators like "+49*810", whose leading '+' is optional
+func parseDinOperator(input string) (DinOperator, error) {
+	callingCode, operatorCode, found := strings.Cut(input, "*")
+	if !found {
+		return DinOperator{}, fmt.Errorf("%w: expected a DIN operator like \"+49*810\", got %q", c.ErrInvalidFormat, input)
+	}
+
+	// the power outlet ID is only there to validate the other fields
+	id, err := din.NewEvseId(callingCode, operatorCode, "0")
+	if err != nil {
+		return DinOperator{}, err
+	}
+
+	return DinOperator{CallingCode: id.CountryCode(), OperatorCode: id.OperatorCode()}, nil
+}
+
+// parseIsoOperator parses operators like "DE*8EO", whose country code is derived from callingCode if missing
+func parseIsoOperator(input, callingCode string) (IsoOperator, error) {
+	countryCode, operatorCode, found := strings.Cut(input, "*")
+	if !found {
+		countries := c.CountriesByCallingCode(callingCode)
+		if len(countries) != 1 {
+			return IsoOperator{}, fmt.Errorf("%w: %v is used by %v", ErrAmbiguousCallingCode, callingCode, countries)
+		}
+
+		countryCode, operatorCode = countries[0], input
+	}
+
+	id, err := iso.NewEvseId(countryCode, operatorCode, "0")
+	if err != nil {
+		return IsoOperator{}, err
+	}
+
+	if c.CallingCode(id.CountryCode()) != callingCode {
+		return IsoOperator{}, fmt.Errorf("%w: %v doesn't use %v", ErrCountryMismatch, id.CountryCode(), callingCode)
+	}
+
+	return IsoOperator{CountryCode: id.CountryCode(), OperatorCode: id.OperatorCode()}, nil
+}
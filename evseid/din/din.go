@@ -1,97 +1,154 @@
 package din
 
 import (
-	"fmt"
-	v "github.com/go-ozzo/ozzo-validation"
+	"math/rand"
 	c "mobilityid/common"
 	"mobilityid/evseid"
-	"regexp"
+	"mobilityid/grammar"
 	"strings"
-	"unicode"
 )
 
-var regex = regexp.MustCompile(fmt.Sprintf("^(?P<country>%v)\\*(?P<operator>%v)\\*(?P<outlet>%v)$", "\\+?([0-9]{1,3})", "([0-9]{3,6})", "([0-9\\*]{1,32})"))
+// Grammar describes the syntax of DIN EVSE IDs, e.g. "+49*810*000*438"; the leading '+' of the country code is
+// optional when parsing.
+var Grammar = &grammar.Grammar{
+	Kind:              c.KindEvse,
+	Format:            c.FormatDin,
+	Separators:        "*",
+	SeparatorRequired: true,
+	Segments: []grammar.Segment{
+		{Field: c.FieldCountryCode, Alphabet: grammar.Digits, MinLength: 1, MaxLength: 3, Marker: "+", MarkerOptional: true, CallingCode: true},
+		{Field: c.FieldOperatorCode, Alphabet: grammar.Digits, MinLength: 3, MaxLength: 6},
+		{Field: c.FieldPowerOutletId, Alphabet: grammar.Digits, Extra: "*", MinLength: 1, MaxLength: 32},
+	},
+}
+
+// parser parses DIN EVSE IDs, as described by Grammar
+var parser = grammar.Parser[EvseId]{Grammar: Grammar, Build: fromMatch}
+
+func init() {
+	evseid.RegisterFormat(c.FormatDin, func(input string, opts grammar.ParseOptions) (evseid.Reader, error) {
+		return ParseWith(input, opts)
+	})
+}
 
 // EvseId represents a DIN Evse Id
 type EvseId struct {
-	evseid.Reader
+	evseid.Id
 }
 
-func (c EvseId) String() string {
-	result := strings.Join([]string{c.CountryCode(), c.OperatorCode(), c.PowerOutletId()}, "*")
+// Kind returns the kind of this identifier
+func (id EvseId) Kind() c.Kind {
+	return Grammar.Kind
+}
 
-	return result
+// Format returns the format of this identifier
+func (id EvseId) Format() c.Format {
+	return Grammar.Format
 }
 
-// NewEvseId returns a DIN EvseId, if provided input is valid; returns an error otherwise.
-func NewEvseId(countryCode, operatorCode, powerOutletId string) (*EvseId, error) {
-	if err := validate(countryCode, operatorCode, powerOutletId); err != nil {
-		return nil, err
+func (c EvseId) String() string {
+	if c.IsZero() {
+		return ""
 	}
 
-	return Parse(strings.Join([]string{countryCode, operatorCode, powerOutletId}, "*"))
+	return Grammar.Join(values(c.Id), Grammar.Separator())
 }
 
-// Parse parses the input string into a EvseId, if it is valid; returns an error otherwise.
-func Parse(input string) (*EvseId, error) {
-	groups := regex.FindStringSubmatch(input)
+// CompactString returns the ID without any separator. It is lossy, e.g. "+49*810*000*438" and "+49*810*000438" have the
+// same compact string, so IDs should be compared and stored in their Canonical form.
+func (c EvseId) CompactString() string {
+	return strings.ReplaceAll(c.String(), "*", "")
+}
 
-	countryCode, err := c.ExtractAndUpcaseGroup(regex, groups, "country", true)
-	if err != nil {
-		return nil, fmt.Errorf("not a DIN EvseId: %v", input)
-	}
-	if !strings.HasPrefix(countryCode, "+") {
-		countryCode = "+" + countryCode
-	}
-	operatorId, err := c.ExtractAndUpcaseGroup(regex, groups, "operator", true)
-	if err != nil {
-		return nil, fmt.Errorf("not a DIN EvseId: %v", input)
+// Canonical returns the canonical form of the ID, which identifies it whatever its spelling: with the leading '+' of
+// the country code, and '*' after the country and operator codes, keeping the separators within the power outlet ID,
+// which are significant, e.g. "+49*810*000*438" for "49*810*000*438".
+func (c EvseId) Canonical() string {
+	return strings.ToUpper(c.String())
+}
+
+// NewEvseId returns a DIN EvseId, if provided input is valid; returns an error otherwise.
+// The leading '+' of the country code is optional.
+func NewEvseId(countryCode, operatorCode, powerOutletId string) (EvseId, error) {
+	v := []string{strings.TrimPrefix(countryCode, "+"), operatorCode, powerOutletId}
+	if err := Grammar.Validate(v); err != nil {
+		return EvseId{}, err
 	}
-	outletId, err := c.ExtractAndUpcaseGroup(regex, groups, "outlet", true)
+
+	return fromValues(v), nil
+}
+
+// NewEvseIdFromSegments returns a DIN EvseId whose power outlet ID is made of segments, e.g. a station serial number
+// and a connector index (see evseid.JoinSegments), if provided input is valid; returns an error otherwise.
+func NewEvseIdFromSegments(countryCode, operatorCode string, segments ...string) (EvseId, error) {
+	powerOutletId, err := evseid.JoinSegments(segments...)
 	if err != nil {
-		return nil, fmt.Errorf("not a DIN EvseId: %v", input)
+		var errs c.ValidationErrors
+		errs.Add(c.FieldPowerOutletId, err)
+
+		return EvseId{}, errs
 	}
 
-	return &EvseId{
-		evseid.Id(
-			countryCode,
-			operatorId,
-			outletId,
-		),
-	}, nil
+	return NewEvseId(countryCode, operatorCode, powerOutletId)
 }
 
-func validate(countryCode, operatorCode, powerOutletId string) error {
-	code := countryCode
-	if strings.HasPrefix(countryCode, "+") {
-		code = countryCode[1:]
-	}
+// Parse parses the input string into a EvseId, if it is valid; returns an error otherwise.
+// It doesn't allocate if input is valid and its country code has the leading '+'.
+func Parse(input string) (EvseId, error) {
+	return ParseWith(input, grammar.ParseOptions{})
+}
 
-	if err := v.Validate(code, v.Required, v.Length(2, 4)); err != nil {
-		return err
-	}
+// ParseWith parses input like Parse, as strictly as required by opts
+func ParseWith(input string, opts grammar.ParseOptions) (EvseId, error) {
+	return parser.ParseWith(input, opts)
+}
 
-	err := v.Validate(code, v.By(func(value interface{}) error {
-		num := value.(string)
-		for _, r := range num {
-			if !unicode.IsDigit(r) {
-				return fmt.Errorf("country code '%s' can only contain a leading (optional) '+' following by 1 to 3 digits", countryCode)
-			}
-		}
+// ParseBytes parses input like Parse; it allocates once, if input is valid (see grammar.Parser.ParseBytes)
+func ParseBytes(input []byte) (EvseId, error) {
+	return parser.ParseBytes(input)
+}
 
-		return nil
-	}))
-	if err != nil {
-		return err
-	}
+// Check returns the error Parse would return for input, without building the ID: it doesn't allocate if input is valid.
+func Check(input string) error {
+	return parser.Check(input)
+}
+
+// CheckBytes is like Check, but takes a byte slice
+func CheckBytes(input []byte) error {
+	return parser.CheckBytes(input)
+}
+
+// Generate returns a random, valid, DIN EvseId
+func Generate(r *rand.Rand) EvseId {
+	return fromValues(Grammar.Generate(r))
+}
 
-	if err := v.Validate(operatorCode, v.Required, v.Length(2, 6)); err != nil {
-		return err
+// Validate validates the fields of this ID, returning a common.ValidationErrors listing every invalid one.
+func (id EvseId) Validate() error {
+	if id.CountryCode() == "" {
+		return c.ValidationErrors{{Field: c.FieldCountryCode, Err: c.ErrRequired}}
 	}
 
-	if err := v.Validate(powerOutletId, v.Required, v.Length(1, 31)); err != nil {
-		return err
+	if !strings.HasPrefix(id.CountryCode(), "+") {
+		return c.ValidationErrors{{Field: c.FieldCountryCode, Value: id.CountryCode(), Err: c.ErrInvalidCharacter}}
 	}
 
-	return nil
+	return evseid.Validate(Grammar, id.Id)
+}
+
+// values returns the values of the segments of id, whose country code includes the leading '+' marker
+func values(id evseid.Id) []string {
+	v := evseid.Values(id)
+	v[0] = strings.TrimPrefix(v[0], "+")
+
+	return v
+}
+
+func fromValues(v []string) EvseId {
+	return EvseId{evseid.NewId("+"+v[0], v[1], v[2])}
+}
+
+// fromMatch returns the ID matched in input, reusing the leading '+' of the country code if present
+func fromMatch(input string, m grammar.Match) EvseId {
+	return EvseId{evseid.FromMarkedMatch(input, m, "+")}
 }
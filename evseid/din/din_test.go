@@ -1,8 +1,12 @@
 package din
 
 import (
+	"errors"
 	"github.com/stretchr/testify/assert"
+	"math/rand"
+	c "mobilityid/common"
 	"mobilityid/evseid"
+	"mobilityid/grammar"
 	"testing"
 )
 
@@ -12,7 +16,7 @@ var (
 		OperatorCode:  "810",
 		PowerOutletId: "000*438",
 	}
-	expectedId    = &EvseId{evseid.Id(input.CountryCode, input.OperatorCode, input.PowerOutletId)}
+	expectedId    = EvseId{evseid.NewId(input.CountryCode, input.OperatorCode, input.PowerOutletId)}
 	assertValidId = evseid.NewAssertValidId(input.CountryCode, input.OperatorCode, input.PowerOutletId)
 )
 
@@ -111,3 +115,201 @@ func TestNewEvseId(t *testing.T) {
 		})
 	}
 }
+
+func TestEvseId_IsZero(t *testing.T) {
+	t.Run("returns true for the zero value, which can be used without panicking", func(t *testing.T) {
+		id := EvseId{}
+
+		assert.True(t, id.IsZero())
+		assert.Equal(t, "", id.String())
+		assert.Equal(t, "", id.PartyId())
+	})
+
+	t.Run("returns false for a non-empty ID", func(t *testing.T) {
+		assert.False(t, expectedId.IsZero())
+	})
+}
+
+func TestEvseId_Equality(t *testing.T) {
+	t.Run("compares IDs by value, so that they can be used as map keys", func(t *testing.T) {
+		created, err := NewEvseId(input.CountryCode, input.OperatorCode, input.PowerOutletId)
+		assert.Nil(t, err)
+
+		assert.True(t, created == expectedId)
+		assert.True(t, map[EvseId]bool{created: true}[expectedId])
+	})
+}
+
+func TestEvseId_Validate(t *testing.T) {
+	cases := []struct {
+		name        string
+		id          EvseId
+		expectedErr error
+	}{
+		{
+			name: "accepts a valid ID",
+			id:   expectedId,
+		},
+		{
+			name:        "rejects the zero value",
+			id:          EvseId{},
+			expectedErr: c.ErrRequired,
+		},
+		{
+			name:        "rejects an empty country code",
+			id:          EvseId{evseid.NewId("", input.OperatorCode, input.PowerOutletId)},
+			expectedErr: c.ErrRequired,
+		},
+		{
+			name:        "rejects a country code without '+'",
+			id:          EvseId{evseid.NewId("49", input.OperatorCode, input.PowerOutletId)},
+			expectedErr: c.ErrInvalidCharacter,
+		},
+		{
+			name:        "rejects an ID with invalid characters",
+			id:          EvseId{evseid.NewId(input.CountryCode, input.OperatorCode, "12-34")},
+			expectedErr: c.ErrInvalidPowerOutletId,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.id.Validate()
+
+			if test.expectedErr == nil {
+				assert.Nil(t, err)
+				return
+			}
+
+			assert.True(t, errors.Is(err, test.expectedErr), err)
+		})
+	}
+}
+
+func TestEvseId_Identifier(t *testing.T) {
+	t.Run("implements the Identifier interface", func(t *testing.T) {
+		var id c.Identifier = expectedId
+
+		assert.Equal(t, c.KindEvse, id.Kind())
+		assert.Equal(t, c.FormatDin, id.Format())
+		assert.Equal(t, expectedId.PartyId(), id.PartyId())
+		assert.Equal(t, "DEU", id.Country().Alpha3)
+	})
+}
+
+func TestEvseId_CompactString(t *testing.T) {
+	t.Run("returns a valid DIN string without separators", func(t *testing.T) {
+		assert.Equal(t, "+49810000438", expectedId.CompactString())
+	})
+}
+
+func TestEvseId_Canonical(t *testing.T) {
+	t.Run("returns a DIN string with the leading '+' and separators", func(t *testing.T) {
+		id, err := Parse("49*810*000*438")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "+49*810*000*438", id.Canonical())
+	})
+}
+
+func TestNewEvseId_Errors(t *testing.T) {
+	t.Run("returns ErrInvalidOperatorCode for a non numeric operator code", func(t *testing.T) {
+		_, err := NewEvseId(input.CountryCode, "TNMA", input.PowerOutletId)
+
+		assert.True(t, errors.Is(err, c.ErrInvalidOperatorCode))
+		assert.True(t, errors.Is(err, c.ErrInvalidCharacter))
+	})
+
+	t.Run("returns ErrInvalidFormat for a string not matching the format", func(t *testing.T) {
+		_, err := Parse("XYZ")
+
+		assert.True(t, errors.Is(err, c.ErrInvalidFormat))
+	})
+
+	t.Run("returns the error of the invalid segment of a string not matching the format", func(t *testing.T) {
+		_, err := Parse("+49*81A*000*438")
+
+		assert.True(t, errors.Is(err, c.ErrInvalidFormat))
+		assert.True(t, errors.Is(err, c.ErrInvalidOperatorCode))
+		assert.True(t, errors.Is(err, c.ErrInvalidCharacter))
+	})
+}
+
+func TestGenerate(t *testing.T) {
+	t.Run("generates valid EVSE IDs, which can be parsed back", func(t *testing.T) {
+		r := rand.New(rand.NewSource(1))
+
+		for i := 0; i < 100; i++ {
+			id := Generate(r)
+			assert.Nil(t, id.Validate())
+
+			parsed, err := Parse(id.String())
+			assert.Nil(t, err)
+			assert.Equal(t, id, parsed)
+		}
+	})
+}
+
+func TestParseBytes(t *testing.T) {
+	id, err := ParseBytes([]byte(expectedId.String()))
+
+	assert.Nil(t, err)
+	assert.Equal(t, expectedId, id)
+	assert.Nil(t, Check(expectedId.String()))
+	assert.Nil(t, CheckBytes([]byte(expectedId.String())))
+
+	_, expectedErr := Parse("invalid")
+	_, err = ParseBytes([]byte("invalid"))
+
+	assert.NotNil(t, err)
+	assert.Equal(t, expectedErr, err)
+	assert.Equal(t, expectedErr, Check("invalid"))
+	assert.Equal(t, expectedErr, CheckBytes([]byte("invalid")))
+}
+
+func TestParseWith(t *testing.T) {
+	_, err := ParseWith("49*810*000*438", grammar.ParseOptions{RequireMarkers: true})
+	assert.True(t, errors.Is(err, c.ErrInvalidFormat))
+
+	id, err := ParseWith(" +49*810*000*438 ", grammar.ParseOptions{TrimSpace: true})
+	assert.Nil(t, err)
+	assert.Equal(t, expectedId, id)
+
+	eea := grammar.ParseOptions{Countries: &c.CountryPolicy{Allowed: c.EEA}}
+	_, err = ParseWith("+49*810*000*438", eea)
+	assert.Nil(t, err)
+	_, err = ParseWith("+1*810*000*438", eea)
+	assert.True(t, errors.Is(err, c.ErrCountryNotAllowed))
+}
+
+func TestEvseId_Country(t *testing.T) {
+	t.Run("returns the country of a calling code used by a single country", func(t *testing.T) {
+		assert.Equal(t, "Germany", expectedId.Country().Name)
+	})
+
+	t.Run("returns the primary country of a calling code shared by many countries", func(t *testing.T) {
+		id, err := Parse("+39*810*000*438")
+		assert.Nil(t, err)
+
+		assert.Equal(t, "Italy", id.Country().Name)
+	})
+
+	t.Run("returns the zero Country for an unknown calling code", func(t *testing.T) {
+		id, err := Parse("+999*123*1")
+		assert.Nil(t, err)
+
+		assert.True(t, id.Country().IsZero())
+	})
+
+	t.Run("returns the country resolved by the country policy", func(t *testing.T) {
+		policy := &c.CountryPolicy{Allowed: []string{"VA", "XK"}, UserAssigned: []c.Country{c.Kosovo}}
+
+		for input, expected := range map[string]string{"39*810*000*438": "Vatican City", "+383*123*1": "Kosovo"} {
+			id, err := ParseWith(input, grammar.ParseOptions{Countries: policy})
+			assert.Nil(t, err, input)
+
+			assert.Equal(t, expected, id.Country().Name, input)
+			assert.Nil(t, id.Validate(), input)
+		}
+	})
+}
@@ -1,50 +1,150 @@
 package evseid
 
+import (
+	c "mobilityid/common"
+	"mobilityid/grammar"
+	"strings"
+)
+
 // Reader provides functions to read fields of evse IDs
 type Reader interface {
 	CountryCode() string
 	OperatorCode() string
 	PowerOutletId() string
+	Segments() []string
+	Canonical() string
 	PartyId() string
 	CompactPartyId() string
+	IsZero() bool
+	Validate() error
+	c.Identifier
 }
 
-type evseId struct {
+// Id holds the fields shared by all EVSE ID formats.
+//
+// Like contract IDs, EVSE IDs are equal (==) if all their fields are, including the country a country policy resolved
+// their country code to, if any (see contractid.Id); Equal only compares the EVSEs they identify.
+type Id struct {
 	countryCode   string
 	operatorCode  string
 	powerOutletId string
-	Reader
+	// country is the country resolved by the country policy the ID was parsed with, if any (see grammar.Match.Country)
+	country c.Country
 }
 
-func Id(countryCode, partyCode, powerOutletId string) *evseId {
-	return &evseId{
+// NewId returns an Id made of the provided fields, as they are: it doesn't validate nor normalize them.
+func NewId(countryCode, operatorCode, powerOutletId string) Id {
+	return Id{
 		countryCode:   countryCode,
-		operatorCode:  partyCode,
+		operatorCode:  operatorCode,
 		powerOutletId: powerOutletId,
 	}
 }
 
 // CountryCode returns the country code
-func (id *evseId) CountryCode() string {
+func (id Id) CountryCode() string {
 	return id.countryCode
 }
 
+// Country returns the country of the country code, which is the ITU calling code of DIN EVSE IDs (e.g. "+49").
+// A calling code shared by many countries (e.g. "+39") returns its primary country (e.g. Italy), as only the operator
+// tells which one it really is. It returns the zero Country if the country code is unknown. The country resolved by the
+// country policy the ID was parsed with, if any, is returned for aliases, user-assigned and test codes, and for calling
+// codes whose primary country isn't allowed.
+func (id Id) Country() c.Country {
+	if !id.country.IsZero() {
+		return id.country
+	}
+
+	return c.CountryOf(id.countryCode)
+}
+
 // OperatorCode returns the party code
-func (id *evseId) OperatorCode() string {
+func (id Id) OperatorCode() string {
 	return id.operatorCode
 }
 
 // PowerOutletId returns the power outlet ID
-func (id *evseId) PowerOutletId() string {
+func (id Id) PowerOutletId() string {
 	return id.powerOutletId
 }
 
+// IsZero returns true if this is the zero value, i.e. none of its fields has been set
+func (id Id) IsZero() bool {
+	return id == Id{}
+}
+
 // PartyId returns the party ID
-func (id *evseId) PartyId() string {
+func (id Id) PartyId() string {
+	if id.IsZero() {
+		return ""
+	}
+
 	return id.CountryCode() + "-" + id.OperatorCode()
 }
 
 // CompactPartyId returns the party ID without separator
-func (id *evseId) CompactPartyId() string {
+func (id Id) CompactPartyId() string {
 	return id.CountryCode() + id.OperatorCode()
 }
+
+// Values returns the values of the fields of id, in the order of the segments of EVSE ID grammars: country code,
+// operator code and power outlet ID
+func Values(id Id) []string {
+	return []string{id.CountryCode(), id.OperatorCode(), id.PowerOutletId()}
+}
+
+// FromValues returns an Id made of values, in the order of the segments of EVSE ID grammars (see Values)
+func FromValues(values []string) Id {
+	return NewId(values[0], values[1], values[2])
+}
+
+// FromMatch returns an Id made of the values matched in input by an EVSE ID grammar, in upper case.
+// It doesn't allocate if they already are in upper case.
+func FromMatch(input string, m grammar.Match) Id {
+	return fromMatch(strings.ToUpper(m.Value(input, 0)), input, m)
+}
+
+// FromMarkedMatch is like FromMatch, but keeps the marker of the country code, e.g. the leading '+' of DIN EVSE IDs,
+// adding marker if it is missing from input
+func FromMarkedMatch(input string, m grammar.Match, marker string) Id {
+	countryCode := m.Marked(input, 0)
+	if !m.HasMarker(0) {
+		countryCode = marker + countryCode
+	}
+
+	return fromMatch(countryCode, input, m)
+}
+
+func fromMatch(countryCode, input string, m grammar.Match) Id {
+	id := NewId(countryCode, strings.ToUpper(m.Value(input, 1)), strings.ToUpper(m.Value(input, 2)))
+	id.country = m.Country()
+
+	return id
+}
+
+// Validate validates the fields of id according to g, in upper case, returning a common.ValidationErrors listing every
+// invalid one; the marker of the country code, if any, must be part of it. Its country code is accepted if it has been
+// resolved by the country policy id was parsed with.
+func Validate(g *grammar.Grammar, id Id) error {
+	values := Values(id)
+	values[0] = strings.TrimPrefix(values[0], g.Segments[0].Marker)
+
+	return g.ValidateWith(values, grammar.ParseOptions{
+		RejectLowercase: true,
+		Countries:       c.Accepting(id.countryCode, id.country),
+	})
+}
+
+// Within returns true if id is operated by the operator identified by countryCode and operatorCode, and if its power
+// outlet ID starts with prefix, followed by '*' or nothing, ignoring case: this is how operators usually number the
+// EVSEs of a charging pool or station, e.g. "NL*TNM*E030123456*0" is within "NL", "TNM" and "030123456".
+func Within(id Reader, countryCode, operatorCode, prefix string) bool {
+	powerOutletId := id.PowerOutletId()
+	if prefix == "" || len(powerOutletId) < len(prefix) || compareOperator(id, countryCode, operatorCode) != 0 {
+		return false
+	}
+
+	return strings.EqualFold(powerOutletId[:len(prefix)], prefix) &&
+		(len(powerOutletId) == len(prefix) || powerOutletId[len(prefix)] == '*')
+}
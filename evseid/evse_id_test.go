@@ -0,0 +1,38 @@
+package evseid_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"mobilityid/evseid"
+	"mobilityid/evseid/din"
+	"testing"
+)
+
+func TestWithin(t *testing.T) {
+	dinId, _ := din.Parse("+49*810*000*438")
+
+	cases := []struct {
+		name     string
+		id       evseid.Reader
+		prefix   string
+		expected bool
+	}{
+		{"followed by a separator", mustParseIso("NL*TNM*E030123456*0"), "030123456", true},
+		{"equal", mustParseIso("NL*TNM*E030123456"), "030123456", true},
+		{"in any case", mustParseIso("NL*TNM*EAB12*0"), "ab12", true},
+		{"nested", mustParseIso("NL*TNM*E03*01*2"), "03*01", true},
+		{"not at a segment boundary", mustParseIso("NL*TNM*E0301234560"), "030123456", false},
+		{"other prefix", mustParseIso("NL*TNM*E040123456*0"), "030123456", false},
+		{"longer prefix", mustParseIso("NL*TNM*E03"), "030", false},
+		{"empty prefix", mustParseIso("NL*TNM*E03"), "", false},
+		{"DIN EVSE ID", dinId, "000", false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, evseid.Within(tc.id, "NL", "TNM", tc.prefix))
+		})
+	}
+
+	assert.False(t, evseid.Within(mustParseIso("DE*TNM*E03*0"), "NL", "TNM", "03"))
+	assert.False(t, evseid.Within(mustParseIso("NL*ABC*E03*0"), "NL", "TNM", "03"))
+}
@@ -1,37 +1,78 @@
 package iso
 
 import (
-	"fmt"
-	v "github.com/go-ozzo/ozzo-validation"
+	"math/rand"
 	c "mobilityid/common"
 	"mobilityid/evseid"
-	"regexp"
+	"mobilityid/grammar"
 	"strings"
 )
 
-var regex = regexp.MustCompile(fmt.Sprintf("^(?P<country>%v)(?:\\*?)(?P<operator>%v)(?:\\*?)%v(?P<outlet>%v)$", c.CountryCodeRegex, c.PartyCodeRegex, "[Ee]", "([A-Za-z0-9\\*]{1,31})"))
+// Grammar describes the syntax of ISO EVSE IDs, e.g. "DE*AB7*E840*6487"
+var Grammar = &grammar.Grammar{
+	Kind:       c.KindEvse,
+	Format:     c.FormatIso,
+	Separators: "*",
+	Segments: []grammar.Segment{
+		grammar.CountryCode,
+		{Field: c.FieldOperatorCode, Alphabet: grammar.Alphanumeric, MinLength: 3, MaxLength: 3},
+		{Field: c.FieldPowerOutletId, Alphabet: grammar.Alphanumeric, Extra: "*", MinLength: 1, MaxLength: 31, Marker: "E"},
+	},
+}
+
+// parser parses ISO EVSE IDs, as described by Grammar
+var parser = grammar.Parser[EvseId]{Grammar: Grammar, Build: fromMatch}
+
+func init() {
+	evseid.RegisterFormat(c.FormatIso, func(input string, opts grammar.ParseOptions) (evseid.Reader, error) {
+		return ParseWith(input, opts)
+	})
+}
 
 // EvseId represents an ISO EVSE ID
 type EvseId struct {
-	evseid.Reader
+	evseid.Id
+}
+
+// Kind returns the kind of this identifier
+func (id EvseId) Kind() c.Kind {
+	return Grammar.Kind
+}
+
+// Format returns the format of this identifier
+func (id EvseId) Format() c.Format {
+	return Grammar.Format
 }
 
 func (c EvseId) String() string {
-	return strings.Join([]string{c.CountryCode(), c.OperatorCode(), "E" + c.PowerOutletId()}, "*")
+	if c.IsZero() {
+		return ""
+	}
+
+	return Grammar.Join(evseid.Values(c.Id), Grammar.Separator())
 }
 
+// CompactString returns the ID without any separator. It is lossy, e.g. "NL*TNM*E03*0" and "NL*TNM*E030" have the
+// same compact string, so IDs should be compared and stored in their Canonical form.
 func (c EvseId) CompactString() string {
 	return strings.ReplaceAll(c.String(), "*", "")
 }
 
+// Canonical returns the canonical form of the ID, which identifies it whatever its spelling: upper case, with '*'
+// after the country and operator codes, and the separators within the power outlet ID, which are significant, e.g.
+// "NL*TNM*E03*0" for "nltnme03*0", but not for "NL*TNM*E030".
+func (c EvseId) Canonical() string {
+	return strings.ToUpper(c.String())
+}
+
 // NewEvseId returns an EvseId, if provided input is valid; returns an error otherwise.
-func NewEvseId(countryCode, operatorCode, powerOutletId string) (*EvseId, error) {
-	if err := validate(countryCode, operatorCode, powerOutletId); err != nil {
-		return nil, err
+func NewEvseId(countryCode, operatorCode, powerOutletId string) (EvseId, error) {
+	if err := Grammar.Validate([]string{countryCode, operatorCode, powerOutletId}); err != nil {
+		return EvseId{}, err
 	}
 
-	return &EvseId{
-		evseid.Id(
+	return EvseId{
+		evseid.NewId(
 			strings.ToUpper(countryCode),
 			strings.ToUpper(operatorCode),
 			strings.ToUpper(powerOutletId),
@@ -39,61 +80,56 @@ func NewEvseId(countryCode, operatorCode, powerOutletId string) (*EvseId, error)
 	}, nil
 }
 
-// Parse parses the input string into an EvseId, if it is valid; returns an error otherwise.
-func Parse(input string) (*EvseId, error) {
-	groups := regex.FindStringSubmatch(input)
-
-	countryCode, err := c.ExtractAndUpcaseGroup(regex, groups, "country", true)
+// NewEvseIdFromSegments returns an EvseId whose power outlet ID is made of segments, e.g. a station serial number
+// and a connector index (see evseid.JoinSegments), if provided input is valid; returns an error otherwise.
+func NewEvseIdFromSegments(countryCode, operatorCode string, segments ...string) (EvseId, error) {
+	powerOutletId, err := evseid.JoinSegments(segments...)
 	if err != nil {
-		return nil, fmt.Errorf("not an ISO EvseId: %v", input)
-	}
+		var errs c.ValidationErrors
+		errs.Add(c.FieldPowerOutletId, err)
 
-	if !c.IsValidCountryCode(countryCode) {
-		return nil, fmt.Errorf("invalid country code: %v", countryCode)
+		return EvseId{}, errs
 	}
 
-	operatorCode, err := c.ExtractAndUpcaseGroup(regex, groups, "operator", true)
-	if err != nil {
-		return nil, fmt.Errorf("not an ISO EvseId: %v", input)
-	}
-	outletId, err := c.ExtractAndUpcaseGroup(regex, groups, "outlet", true)
-	if err != nil {
-		return nil, fmt.Errorf("not an ISO EvseId: %v", input)
-	}
+	return NewEvseId(countryCode, operatorCode, powerOutletId)
+}
 
-	return &EvseId{
-		evseid.Id(
-			countryCode,
-			operatorCode,
-			outletId,
-		),
-	}, nil
+// Parse parses the input string into an EvseId, if it is valid; returns an error otherwise.
+// It doesn't allocate if input is valid and in upper case.
+func Parse(input string) (EvseId, error) {
+	return ParseWith(input, grammar.ParseOptions{})
 }
 
-func validate(countryCode, operatorCode, powerOutletId string) error {
-	err := v.Validate(
-		countryCode,
-		v.Required,
-		v.Length(2, 2),
-		v.By(
-			func(value interface{}) error {
-				if !c.IsValidCountryCode(value.(string)) {
-					return fmt.Errorf("country code '%s' is not valid", value.(string))
-				}
-				return nil
-			}),
-	)
-	if err != nil {
-		return err
-	}
+// ParseWith parses input like Parse, as strictly as required by opts
+func ParseWith(input string, opts grammar.ParseOptions) (EvseId, error) {
+	return parser.ParseWith(input, opts)
+}
 
-	if err := v.Validate(operatorCode, v.Required, v.Length(3, 3)); err != nil {
-		return err
-	}
+// ParseBytes parses input like Parse; it allocates once, if input is valid (see grammar.Parser.ParseBytes)
+func ParseBytes(input []byte) (EvseId, error) {
+	return parser.ParseBytes(input)
+}
 
-	if err := v.Validate(powerOutletId, v.Required, v.Length(1, 31)); err != nil {
-		return err
-	}
+// Check returns the error Parse would return for input, without building the ID: it doesn't allocate if input is valid.
+func Check(input string) error {
+	return parser.Check(input)
+}
+
+// CheckBytes is like Check, but takes a byte slice
+func CheckBytes(input []byte) error {
+	return parser.CheckBytes(input)
+}
+
+func fromMatch(input string, m grammar.Match) EvseId {
+	return EvseId{evseid.FromMatch(input, m)}
+}
+
+// Generate returns a random, valid, ISO EvseId
+func Generate(r *rand.Rand) EvseId {
+	return EvseId{evseid.FromValues(Grammar.Generate(r))}
+}
 
-	return nil
+// Validate validates the fields of this ID, returning a common.ValidationErrors listing every invalid one.
+func (id EvseId) Validate() error {
+	return evseid.Validate(Grammar, id.Id)
 }
@@ -1,7 +1,10 @@
 package iso
 
 import (
+	"errors"
 	"github.com/stretchr/testify/assert"
+	"math/rand"
+	c "mobilityid/common"
 	"mobilityid/evseid"
 	"testing"
 )
@@ -12,7 +15,7 @@ var (
 		OperatorCode:  "AB7",
 		PowerOutletId: "840*6487",
 	}
-	expectedId           = &EvseId{evseid.Id(input.CountryCode, input.OperatorCode, input.PowerOutletId)}
+	expectedId           = EvseId{evseid.NewId(input.CountryCode, input.OperatorCode, input.PowerOutletId)}
 	assertValidId        = evseid.NewAssertValidId(input.CountryCode, input.OperatorCode, input.PowerOutletId)
 	assertValidCompactId = evseid.NewAssertValidCompactId(input.CountryCode, input.OperatorCode, input.PowerOutletId)
 )
@@ -25,11 +28,41 @@ func TestEvseId_String(t *testing.T) {
 
 func TestEvseId_CompactString(t *testing.T) {
 	t.Run("returns a valid ISO string without separators", func(t *testing.T) {
-		id := &EvseId{evseid.Id(input.CountryCode, input.OperatorCode, "8406487")}
+		id := EvseId{evseid.NewId(input.CountryCode, input.OperatorCode, "8406487")}
 		assert.Equal(t, "DEAB7E8406487", id.CompactString())
 	})
 }
 
+func TestEvseId_Canonical(t *testing.T) {
+	cases := []struct {
+		name     string
+		id       EvseId
+		expected string
+	}{
+		{
+			name:     "keeps separators within the power outlet ID",
+			id:       expectedId,
+			expected: "DE*AB7*E840*6487",
+		},
+		{
+			name:     "is distinct from the ID without separators within the power outlet ID",
+			id:       EvseId{evseid.NewId("NL", "TNM", "030")},
+			expected: "NL*TNM*E030",
+		},
+		{
+			name:     "is in upper case",
+			id:       EvseId{evseid.NewId("nl", "tnm", "03*0")},
+			expected: "NL*TNM*E03*0",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.id.Canonical())
+		})
+	}
+}
+
 func TestParse(t *testing.T) {
 	cases := []struct {
 		name          string
@@ -124,3 +157,138 @@ func TestNewEvseId(t *testing.T) {
 		})
 	}
 }
+
+func TestEvseId_IsZero(t *testing.T) {
+	t.Run("returns true for the zero value, which can be used without panicking", func(t *testing.T) {
+		id := EvseId{}
+
+		assert.True(t, id.IsZero())
+		assert.Equal(t, "", id.String())
+		assert.Equal(t, "", id.PartyId())
+	})
+
+	t.Run("returns false for a non-empty ID", func(t *testing.T) {
+		assert.False(t, expectedId.IsZero())
+	})
+}
+
+func TestEvseId_Equality(t *testing.T) {
+	t.Run("compares IDs by value, so that they can be used as map keys", func(t *testing.T) {
+		created, err := NewEvseId(input.CountryCode, input.OperatorCode, input.PowerOutletId)
+		assert.Nil(t, err)
+
+		assert.True(t, created == expectedId)
+		assert.True(t, map[EvseId]bool{created: true}[expectedId])
+	})
+}
+
+func TestEvseId_Validate(t *testing.T) {
+	cases := []struct {
+		name        string
+		id          EvseId
+		expectedErr bool
+	}{
+		{
+			name: "accepts a valid ID",
+			id:   expectedId,
+		},
+		{
+			name:        "rejects the zero value",
+			id:          EvseId{},
+			expectedErr: true,
+		},
+		{
+			name:        "rejects an ID with invalid characters",
+			id:          EvseId{evseid.NewId(input.CountryCode, input.OperatorCode, "12-34")},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.id.Validate()
+
+			assert.Equal(t, test.expectedErr, err != nil)
+		})
+	}
+}
+
+func TestEvseId_Identifier(t *testing.T) {
+	t.Run("implements the Identifier interface", func(t *testing.T) {
+		var id c.Identifier = expectedId
+
+		assert.Equal(t, c.KindEvse, id.Kind())
+		assert.Equal(t, c.FormatIso, id.Format())
+		assert.Equal(t, expectedId.PartyId(), id.PartyId())
+		assert.Equal(t, "DEU", id.Country().Alpha3)
+	})
+}
+
+func TestNewEvseId_Errors(t *testing.T) {
+	t.Run("reports every invalid field at once", func(t *testing.T) {
+		_, err := NewEvseId("ZZ", "TNMA", "0123456789012345678901234567890123456789")
+
+		var errs c.ValidationErrors
+		assert.True(t, errors.As(err, &errs))
+		assert.Equal(t, []c.Field{c.FieldCountryCode, c.FieldOperatorCode, c.FieldPowerOutletId}, errs.Fields())
+		assert.True(t, errors.Is(err, c.ErrUnknownCountry))
+		assert.True(t, errors.Is(err, c.ErrWrongLength))
+	})
+}
+
+func TestParse_ReportsEveryInvalidField(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected []c.Field
+	}{
+		{input: "ZZ*TNMX*E0301", expected: []c.Field{c.FieldCountryCode, c.FieldOperatorCode}},
+		{input: "NL*TN*0301", expected: []c.Field{c.FieldOperatorCode, c.FieldPowerOutletId}},
+		{input: "ZZ*TNM*E03#01", expected: []c.Field{c.FieldCountryCode, c.FieldPowerOutletId}},
+		{input: "ZZTNMXE0301"},
+	}
+
+	for _, test := range cases {
+		t.Run(test.input, func(t *testing.T) {
+			_, err := Parse(test.input)
+
+			var formatErr *c.FormatError
+			assert.True(t, errors.As(err, &formatErr))
+			assert.Equal(t, len(test.expected), len(formatErr.Fields))
+			if len(test.expected) > 0 {
+				assert.Equal(t, test.expected, formatErr.Fields.Fields())
+			}
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	t.Run("generates valid EVSE IDs, which can be parsed back", func(t *testing.T) {
+		r := rand.New(rand.NewSource(1))
+
+		for i := 0; i < 100; i++ {
+			id := Generate(r)
+			assert.Nil(t, id.Validate())
+
+			parsed, err := Parse(id.String())
+			assert.Nil(t, err)
+			assert.Equal(t, id, parsed)
+		}
+	})
+}
+
+func TestParseBytes(t *testing.T) {
+	id, err := ParseBytes([]byte(expectedId.String()))
+
+	assert.Nil(t, err)
+	assert.Equal(t, expectedId, id)
+	assert.Nil(t, Check(expectedId.String()))
+	assert.Nil(t, CheckBytes([]byte(expectedId.String())))
+
+	_, expectedErr := Parse("invalid")
+	_, err = ParseBytes([]byte("invalid"))
+
+	assert.NotNil(t, err)
+	assert.Equal(t, expectedErr, err)
+	assert.Equal(t, expectedErr, Check("invalid"))
+	assert.Equal(t, expectedErr, CheckBytes([]byte("invalid")))
+}
@@ -0,0 +1,143 @@
+package evseid
+
+import (
+	"errors"
+	"fmt"
+	c "mobilityid/common"
+	"strconv"
+	"strings"
+)
+
+// Names of the segments of power outlet IDs commonly used by operators in an OutletSchema
+const (
+	// SegmentStation is the serial number of the charging station
+	SegmentStation = "station"
+	// SegmentConnector is the index of the connector within the charging station
+	SegmentConnector = "connector"
+)
+
+// Segments returns the '*' separated segments of the power outlet ID, e.g. ["030123456", "0"] for "030123456*0"
+func (id Id) Segments() []string {
+	if id.powerOutletId == "" {
+		return nil
+	}
+
+	return strings.Split(id.powerOutletId, "*")
+}
+
+// JoinSegments returns the power outlet ID made of segments, e.g. a station serial number and a connector index; it
+// returns a *common.FieldError if there are none, or if any of them is empty or contains a '*'.
+func JoinSegments(segments ...string) (string, error) {
+	if len(segments) == 0 {
+		return "", &c.FieldError{Field: c.FieldPowerOutletId, Err: c.ErrRequired}
+	}
+
+	for _, segment := range segments {
+		if segment == "" || strings.Contains(segment, "*") {
+			return "", &c.FieldError{Field: c.FieldPowerOutletId, Value: segment, Err: errInvalidSegment}
+		}
+	}
+
+	return strings.Join(segments, "*"), nil
+}
+
+var errInvalidSegment = errors.New("segment must be set, without '*'")
+
+// OutletSchema names the '*' separated segments of power outlet IDs, as structured by an operator, e.g.
+//
+//	evseid.OutletSchema{Segments: []string{evseid.SegmentStation, evseid.SegmentConnector}}
+//
+// for power outlet IDs like "030123456*0".
+type OutletSchema struct {
+	Segments []string
+}
+
+// Outlet is a power outlet ID whose segments are named by an OutletSchema
+type Outlet struct {
+	schema   OutletSchema
+	segments []string
+}
+
+// Split returns the power outlet ID of id, whose segments must match the schema; it returns a *common.FieldError
+// otherwise.
+func (s OutletSchema) Split(id Reader) (Outlet, error) {
+	segments := strings.Split(id.PowerOutletId(), "*")
+	if len(segments) != len(s.Segments) {
+		return Outlet{}, &c.FieldError{
+			Field: c.FieldPowerOutletId,
+			Value: id.PowerOutletId(),
+			Err:   fmt.Errorf("expected %d segments (%s), got %d", len(s.Segments), strings.Join(s.Segments, ", "), len(segments)),
+		}
+	}
+
+	return Outlet{schema: s, segments: segments}, nil
+}
+
+// PowerOutletId returns the power outlet ID made of the segments of the schema, taken from values by name (see
+// JoinSegments); it returns a *common.FieldError if any of them is missing, or if values has segments unknown to the
+// schema.
+// The result is meant to be passed to the NewEvseId constructor of a format, which validates it.
+func (s OutletSchema) PowerOutletId(values map[string]string) (string, error) {
+	for name := range values {
+		if !s.has(name) {
+			return "", &c.FieldError{Field: c.FieldPowerOutletId, Err: fmt.Errorf("unknown segment %q", name)}
+		}
+	}
+
+	segments := make([]string, 0, len(s.Segments))
+	for _, name := range s.Segments {
+		if values[name] == "" {
+			return "", &c.FieldError{Field: c.FieldPowerOutletId, Err: fmt.Errorf("segment %q: %w", name, c.ErrRequired)}
+		}
+
+		segments = append(segments, values[name])
+	}
+
+	return JoinSegments(segments...)
+}
+
+func (s OutletSchema) has(name string) bool {
+	for _, candidate := range s.Segments {
+		if candidate == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Get returns the value of the segment named name, or "" if the schema has no such segment
+func (o Outlet) Get(name string) string {
+	for i, candidate := range o.schema.Segments {
+		if candidate == name {
+			return o.segments[i]
+		}
+	}
+
+	return ""
+}
+
+// Segments returns the values of the segments, in order
+func (o Outlet) Segments() []string {
+	return append([]string(nil), o.segments...)
+}
+
+// Station returns the value of the SegmentStation segment, or "" if the schema has none
+func (o Outlet) Station() string {
+	return o.Get(SegmentStation)
+}
+
+// Connector returns the index of the connector, read from the SegmentConnector segment; it returns an error if the
+// schema has no such segment or if it isn't a number.
+func (o Outlet) Connector() (int, error) {
+	if !o.schema.has(SegmentConnector) {
+		return 0, fmt.Errorf("schema has no %q segment", SegmentConnector)
+	}
+
+	index, err := strconv.Atoi(o.Get(SegmentConnector))
+	if err != nil {
+		return 0, &c.FieldError{Field: c.FieldPowerOutletId, Value: o.Get(SegmentConnector), Err: c.ErrInvalidCharacter}
+	}
+
+	return index, nil
+}
@@ -0,0 +1,109 @@
+package evseid_test
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	c "mobilityid/common"
+	"mobilityid/evseid"
+	"mobilityid/evseid/din"
+	"mobilityid/evseid/iso"
+	"testing"
+)
+
+var stationConnector = evseid.OutletSchema{Segments: []string{evseid.SegmentStation, evseid.SegmentConnector}}
+
+func TestId_Segments(t *testing.T) {
+	assert.Equal(t, []string{"030123456", "0"}, mustParseIso("NL*TNM*E030123456*0").Segments())
+	assert.Equal(t, []string{"030"}, mustParseIso("NL*TNM*E030").Segments())
+	assert.Nil(t, iso.EvseId{}.Segments())
+}
+
+func TestOutletSchema_Split(t *testing.T) {
+	outlet, err := stationConnector.Split(mustParseIso("NL*TNM*E030123456*2"))
+	assert.Nil(t, err)
+	assert.Equal(t, "030123456", outlet.Station())
+	assert.Equal(t, "2", outlet.Get(evseid.SegmentConnector))
+	assert.Equal(t, "", outlet.Get("unknown"))
+	assert.Equal(t, []string{"030123456", "2"}, outlet.Segments())
+
+	connector, err := outlet.Connector()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, connector)
+
+	dinId, _ := din.Parse("+49*810*000*438")
+	outlet, err = stationConnector.Split(dinId)
+	assert.Nil(t, err)
+	assert.Equal(t, "000", outlet.Station())
+
+	_, err = stationConnector.Split(mustParseIso("NL*TNM*E030123456"))
+	assert.True(t, errors.Is(err, c.ErrInvalidPowerOutletId))
+}
+
+func TestOutlet_Connector_Errors(t *testing.T) {
+	outlet, _ := stationConnector.Split(mustParseIso("NL*TNM*E030123456*A"))
+	_, err := outlet.Connector()
+	assert.True(t, errors.Is(err, c.ErrInvalidCharacter))
+
+	stationOnly := evseid.OutletSchema{Segments: []string{evseid.SegmentStation}}
+	outlet, _ = stationOnly.Split(mustParseIso("NL*TNM*E030123456"))
+	_, err = outlet.Connector()
+	assert.NotNil(t, err)
+}
+
+func TestOutletSchema_PowerOutletId(t *testing.T) {
+	powerOutletId, err := stationConnector.PowerOutletId(map[string]string{
+		evseid.SegmentStation:   "030123456",
+		evseid.SegmentConnector: "1",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "030123456*1", powerOutletId)
+
+	cases := []struct {
+		name   string
+		values map[string]string
+		err    error
+	}{
+		{"missing segment", map[string]string{evseid.SegmentStation: "030123456"}, c.ErrRequired},
+		{"empty segment", map[string]string{evseid.SegmentStation: "030123456", evseid.SegmentConnector: ""}, c.ErrRequired},
+		{"segment with separator", map[string]string{evseid.SegmentStation: "03*0", evseid.SegmentConnector: "1"}, c.ErrInvalidPowerOutletId},
+		{"unknown segment", map[string]string{evseid.SegmentStation: "030", evseid.SegmentConnector: "1", "bay": "2"}, c.ErrInvalidPowerOutletId},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := stationConnector.PowerOutletId(tc.values)
+
+			assert.True(t, errors.Is(err, tc.err), err)
+		})
+	}
+}
+
+func TestJoinSegments(t *testing.T) {
+	powerOutletId, err := evseid.JoinSegments("030123456", "0")
+	assert.Nil(t, err)
+	assert.Equal(t, "030123456*0", powerOutletId)
+
+	_, err = evseid.JoinSegments()
+	assert.True(t, errors.Is(err, c.ErrRequired))
+
+	_, err = evseid.JoinSegments("030", "")
+	assert.True(t, errors.Is(err, c.ErrInvalidPowerOutletId))
+}
+
+func TestNewEvseIdFromSegments(t *testing.T) {
+	isoId, err := iso.NewEvseIdFromSegments("NL", "TNM", "030123456", "0")
+	assert.Nil(t, err)
+	assert.Equal(t, "NL*TNM*E030123456*0", isoId.String())
+
+	dinId, err := din.NewEvseIdFromSegments("+49", "810", "000", "438")
+	assert.Nil(t, err)
+	assert.Equal(t, "+49*810*000*438", dinId.String())
+
+	_, err = iso.NewEvseIdFromSegments("NL", "TNM", "03*0")
+	var errs c.ValidationErrors
+	assert.True(t, errors.As(err, &errs))
+	assert.Equal(t, []c.Field{c.FieldPowerOutletId}, errs.Fields())
+
+	_, err = din.NewEvseIdFromSegments("+49", "810", "00A")
+	assert.True(t, errors.Is(err, c.ErrInvalidCharacter))
+}
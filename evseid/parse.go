@@ -0,0 +1,83 @@
+package evseid
+
+import (
+	c "mobilityid/common"
+	"mobilityid/grammar"
+	"sort"
+)
+
+// ParseFunc parses an input string into an EVSE ID of a given format, as strictly as required by opts
+type ParseFunc func(input string, opts grammar.ParseOptions) (Reader, error)
+
+type registeredFormat struct {
+	format c.Format
+	parse  ParseFunc
+}
+
+var formats []registeredFormat
+
+// RegisterFormat makes an EVSE ID format available to Parse. It is called by the format packages at init time,
+// so a format is only detected if its package is imported, e.g.
+//
+//	import _ "mobilityid/evseid/iso"
+//
+// or all of them at once, with
+//
+//	import _ "mobilityid/formats"
+func RegisterFormat(format c.Format, parse ParseFunc) {
+	for i, f := range formats {
+		if f.format == format {
+			formats[i].parse = parse
+			return
+		}
+	}
+
+	formats = append(formats, registeredFormat{format: format, parse: parse})
+	sort.Slice(formats, func(i, j int) bool {
+		return formats[i].format < formats[j].format
+	})
+}
+
+// Parse parses the input string into an EVSE ID, trying all registered formats; its format is returned by Format().
+//
+// ISO EVSE IDs have an alphabetic country code and an 'E' marker before the power outlet ID (e.g. "NL*TNM*E03*0"),
+// while DIN ones are numeric (e.g. "+49*810*000*438"), so an input can't be valid in both formats.
+// It returns a *common.NoMatchError, explaining why each format was rejected, if the input is not valid in any format;
+// it matches common.ErrNoFormats if no format is registered (see RegisterFormat).
+func Parse(input string) (Reader, error) {
+	return Parser{}.Parse(input)
+}
+
+// Parser parses EVSE IDs like Parse, as strictly as required by its Options, e.g.
+//
+//	evseid.Parser{Options: grammar.Strict}.Parse("+49*810*000*438")
+type Parser struct {
+	Options grammar.ParseOptions
+}
+
+// Parse parses the input string into an EVSE ID, trying all registered formats (see evseid.Parse)
+func (p Parser) Parse(input string) (Reader, error) {
+	var rejections []c.Rejection
+
+	for _, f := range formats {
+		id, err := f.parse(input, p.Options)
+		if err == nil {
+			return id, nil
+		}
+
+		rejections = append(rejections, c.Rejection{Format: f.format, Err: err})
+	}
+
+	return nil, &c.NoMatchError{Kind: c.KindEvse, Input: input, Rejections: rejections}
+}
+
+// ParseCanonical parses input like Parse, and returns the canonical form of the EVSE ID it represents, e.g.
+// "NL*TNM*E03*0" for "nltnme03*0", to be used as a key when deduplicating EVSE IDs reported with different spellings.
+func ParseCanonical(input string) (string, error) {
+	id, err := Parse(input)
+	if err != nil {
+		return "", err
+	}
+
+	return id.Canonical(), nil
+}
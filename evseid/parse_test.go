@@ -0,0 +1,115 @@
+package evseid_test
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	c "mobilityid/common"
+	"mobilityid/evseid"
+	_ "mobilityid/evseid/din"
+	_ "mobilityid/evseid/iso"
+	"mobilityid/grammar"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		name           string
+		input          string
+		expectedFormat c.Format
+		expectedString string
+	}{
+		{
+			name:           "detects an ISO EvseId",
+			input:          "DE*AB7*E840*6487",
+			expectedFormat: c.FormatIso,
+			expectedString: "DE*AB7*E840*6487",
+		},
+		{
+			name:           "detects a compact ISO EvseId",
+			input:          "DEAB7E8406487",
+			expectedFormat: c.FormatIso,
+			expectedString: "DE*AB7*E8406487",
+		},
+		{
+			name:           "detects a DIN EvseId",
+			input:          "+49*810*000*438",
+			expectedFormat: c.FormatDin,
+			expectedString: "+49*810*000*438",
+		},
+		{
+			name:           "detects a DIN EvseId without + in country code",
+			input:          "49*810*000*438",
+			expectedFormat: c.FormatDin,
+			expectedString: "+49*810*000*438",
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			id, err := evseid.Parse(test.input)
+
+			assert.Nil(t, err)
+			assert.Equal(t, test.expectedFormat, id.Format())
+			assert.Equal(t, test.expectedString, id.String())
+		})
+	}
+
+	t.Run("explains why each format was rejected if none matches", func(t *testing.T) {
+		_, err := evseid.Parse("DE*AB7*840*6487")
+
+		var noMatch *c.NoMatchError
+		assert.True(t, errors.As(err, &noMatch))
+		assert.Equal(t, c.KindEvse, noMatch.Kind)
+		assert.Len(t, noMatch.Rejections, 2)
+		assert.Equal(t, c.FormatIso, noMatch.Rejections[0].Format)
+		assert.Contains(t, noMatch.Rejections[0].Err.Error(), "'E'")
+		assert.Equal(t, c.FormatDin, noMatch.Rejections[1].Format)
+		assert.Contains(t, noMatch.Rejections[1].Err.Error(), "countryCode of 1 to 3 digits")
+	})
+}
+
+func TestParse_NoFormats(t *testing.T) {
+	evseid.WithoutFormats(func() {
+		_, err := evseid.Parse("+49*810*000*438")
+		assert.True(t, errors.Is(err, c.ErrNoFormats))
+		assert.Contains(t, err.Error(), "mobilityid/formats")
+
+		_, err = evseid.ParseCanonical("+49*810*000*438")
+		assert.True(t, errors.Is(err, c.ErrNoFormats))
+	})
+}
+
+func TestParser_Parse(t *testing.T) {
+	strict := evseid.Parser{Options: grammar.Strict}
+
+	for _, input := range []string{"+49*810*000*438", "DE*AB7*E840*6487"} {
+		id, err := strict.Parse(input)
+
+		assert.Nil(t, err)
+		assert.Equal(t, input, id.String())
+	}
+
+	for _, input := range []string{"49*810*000*438", "de*AB7*E840*6487", "DEAB7E8406487"} {
+		_, err := strict.Parse(input)
+
+		var noMatch *c.NoMatchError
+		assert.True(t, errors.As(err, &noMatch), input)
+	}
+}
+
+func TestParseCanonical(t *testing.T) {
+	for input, expected := range map[string]string{
+		"nl*tnm*e03*0":   "NL*TNM*E03*0",
+		"NLTNME03*0":     "NL*TNM*E03*0",
+		"NL*TNM*E030":    "NL*TNM*E030",
+		"49*810*000*438": "+49*810*000*438",
+	} {
+		actual, err := evseid.ParseCanonical(input)
+
+		assert.Nil(t, err, input)
+		assert.Equal(t, expected, actual, input)
+	}
+
+	_, err := evseid.ParseCanonical("NL*TNM")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,13 @@
+// Package formats registers every contract and EVSE ID format, so that contractid.Parse and evseid.Parse detect all of
+// them; it is meant to be imported for its side effects only:
+//
+//	import _ "mobilityid/formats"
+package formats
+
+import (
+	_ "mobilityid/contractid/din"
+	_ "mobilityid/contractid/emi3"
+	_ "mobilityid/contractid/iso"
+	_ "mobilityid/evseid/din"
+	_ "mobilityid/evseid/iso"
+)
@@ -0,0 +1,28 @@
+package formats
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	c "mobilityid/common"
+	"mobilityid/contractid"
+	"mobilityid/evseid"
+	"testing"
+)
+
+func TestFormats(t *testing.T) {
+	t.Run("registers every contract ID format", func(t *testing.T) {
+		_, err := contractid.Parse("XYZ")
+
+		var noMatch *c.NoMatchError
+		assert.True(t, errors.As(err, &noMatch))
+		assert.Len(t, noMatch.Rejections, 3)
+	})
+
+	t.Run("registers every EVSE ID format", func(t *testing.T) {
+		_, err := evseid.Parse("XYZ")
+
+		var noMatch *c.NoMatchError
+		assert.True(t, errors.As(err, &noMatch))
+		assert.Len(t, noMatch.Rejections, 2)
+	})
+}
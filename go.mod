@@ -3,14 +3,12 @@ module mobilityid
 go 1.19
 
 require (
-	github.com/pariz/gountries v0.0.0-20200430155801-1c6a393df9c7
 	github.com/stretchr/testify v1.7.0
+	golang.org/x/text v0.14.0
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.0 // indirect
-	github.com/go-ozzo/ozzo-validation v3.6.0+incompatible // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	gopkg.in/yaml.v2 v2.4.0 // indirect
 	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c // indirect
 )
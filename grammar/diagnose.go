@@ -0,0 +1,66 @@
+package grammar
+
+import (
+	"fmt"
+	c "mobilityid/common"
+	"strings"
+)
+
+// diagnose returns the invalid values of an input which doesn't match g, so that a *common.FormatError tells why. The
+// input is split at the first separator of g which delimits a value for every segment, the trailing optional one
+// aside; values are then validated one by one, as strictly as required by opts. It returns nil if no separator
+// delimits all values, or if they are all valid, e.g. if the input only lacks a required separator.
+func diagnose(g *Grammar, input string, opts ParseOptions) c.ValidationErrors {
+	for i := 0; i < len(g.Separators); i++ {
+		if values, ok := g.split(input, g.Separators[i:i+1]); ok {
+			return g.validateSplit(values, opts)
+		}
+	}
+
+	return nil
+}
+
+// split splits input at separator into the segments of g, markers included; it returns false if separator doesn't
+// delimit all of them, or if it could be part of a value other than the last one.
+func (g *Grammar) split(input, separator string) ([]string, bool) {
+	n := len(g.Segments)
+	for i := 0; i < n-1; i++ {
+		if strings.Contains(g.Segments[i].Extra, separator) {
+			return nil, false
+		}
+	}
+
+	values := strings.SplitN(input, separator, n)
+	if len(values) == n-1 && g.Segments[n-1].Optional {
+		values = append(values, "")
+	}
+
+	return values, len(values) == n
+}
+
+// validateSplit validates the values returned by split, reporting a missing marker as an invalid format
+func (g *Grammar) validateSplit(values []string, opts ParseOptions) c.ValidationErrors {
+	var errs c.ValidationErrors
+	for i := range g.Segments {
+		s := &g.Segments[i]
+		value := values[i]
+
+		if s.Marker != "" {
+			switch {
+			case len(value) >= len(s.Marker) && strings.EqualFold(value[:len(s.Marker)], s.Marker):
+				value = value[len(s.Marker):]
+			case !s.MarkerOptional || opts.RequireMarkers:
+				errs.Add(s.Field, &c.FieldError{
+					Field: s.Field,
+					Value: value,
+					Err:   fmt.Errorf("%w: expected '%s' before the value", c.ErrInvalidFormat, s.Marker),
+				})
+				continue
+			}
+		}
+
+		errs.Add(s.Field, s.validate(value, opts))
+	}
+
+	return errs
+}
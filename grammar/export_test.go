@@ -0,0 +1,4 @@
+package grammar
+
+// RandomInput exports randomInput to the tests of the format packages' grammars
+var RandomInput = randomInput
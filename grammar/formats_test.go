@@ -0,0 +1,181 @@
+package grammar_test
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	c "mobilityid/common"
+	contractdin "mobilityid/contractid/din"
+	"mobilityid/contractid/emi3"
+	contractiso "mobilityid/contractid/iso"
+	evsedin "mobilityid/evseid/din"
+	evseiso "mobilityid/evseid/iso"
+	"mobilityid/grammar"
+	"mobilityid/poolid"
+	"mobilityid/stationid"
+	"regexp"
+	"testing"
+)
+
+// format exercises the parsing functions of a format package, whatever the type of its identifiers
+type format struct {
+	name       string
+	grammar    *grammar.Grammar
+	input      string
+	parse      func(input string) error
+	parseBytes func(input []byte) error
+	checkBytes func(input []byte) error
+}
+
+func formatOf[T any](name string, g *grammar.Grammar, input string, parse func(string) (T, error), parseBytes func([]byte) (T, error), checkBytes func([]byte) error) format {
+	return format{
+		name:    name,
+		grammar: g,
+		input:   input,
+		parse: func(input string) error {
+			_, err := parse(input)
+			return err
+		},
+		parseBytes: func(input []byte) error {
+			_, err := parseBytes(input)
+			return err
+		},
+		checkBytes: checkBytes,
+	}
+}
+
+var formats = []format{
+	formatOf("ISO contract ID", contractiso.Grammar, "NL-TNM-001234567-X", contractiso.Parse, contractiso.ParseBytes, contractiso.CheckBytes),
+	formatOf("EMI3 contract ID", emi3.Grammar, "NL-TNM-C00122045-K", emi3.Parse, emi3.ParseBytes, emi3.CheckBytes),
+	formatOf("DIN contract ID", contractdin.Grammar, "NL-TNM-012204-5", contractdin.Parse, contractdin.ParseBytes, contractdin.CheckBytes),
+	formatOf("ISO EVSE ID", evseiso.Grammar, "DE*AB7*E840*6487", evseiso.Parse, evseiso.ParseBytes, evseiso.CheckBytes),
+	formatOf("DIN EVSE ID", evsedin.Grammar, "+49*810*000*438", evsedin.Parse, evsedin.ParseBytes, evsedin.CheckBytes),
+	formatOf("charging pool ID", poolid.Grammar, "NL*TNM*P030123456", poolid.Parse, poolid.ParseBytes, poolid.CheckBytes),
+	formatOf("charging station ID", stationid.Grammar, "NL*TNM*S030123456", stationid.Parse, stationid.ParseBytes, stationid.CheckBytes),
+}
+
+// baselines are the regular expressions which parsed each format before grammars, as they were written in its package
+var baselines = []struct {
+	name    string
+	grammar *grammar.Grammar
+	regex   *regexp.Regexp
+	groups  []string // the groups capturing the values of the segments, in order
+}{
+	{
+		name:    "ISO contract ID",
+		grammar: contractiso.Grammar,
+		regex:   regexp.MustCompile(`^(?P<country>([A-Za-z]{2}))(?:-?)(?P<party>([A-Za-z0-9]{3}))(?:-?)(?P<emi3InstanceValue>([A-Za-z0-9]{9}))(?:(?:-?)(?P<check>([A-Za-z0-9])))?$`),
+		groups:  []string{"country", "party", "emi3InstanceValue", "check"},
+	},
+	{
+		name:    "EMI3 contract ID",
+		grammar: emi3.Grammar,
+		regex:   regexp.MustCompile(`^(?P<country>([A-Za-z]{2}))(?:-?)(?P<party>([A-Za-z0-9]{3}))(?:-?)[Cc](?P<emi3InstanceValue>([A-Za-z0-9]{8}))(?:(?:-?)(?P<check>([A-Za-z0-9])))?$`),
+		groups:  []string{"country", "party", "emi3InstanceValue", "check"},
+	},
+	{
+		name:    "DIN contract ID",
+		grammar: contractdin.Grammar,
+		regex:   regexp.MustCompile(`^(?P<country>([A-Za-z]{2}))(?:[*-]?)(?P<party>([A-Za-z0-9]{3}))(?:[*-]?)(?P<instance>([A-Za-z0-9]{6}))(?:(?:[*-]?)(?P<check>([A-Za-z0-9])))?$`),
+		groups:  []string{"country", "party", "instance", "check"},
+	},
+	{
+		name:    "ISO EVSE ID",
+		grammar: evseiso.Grammar,
+		regex:   regexp.MustCompile(`^(?P<country>([A-Za-z]{2}))(?:\*?)(?P<operator>([A-Za-z0-9]{3}))(?:\*?)[Ee](?P<outlet>([A-Za-z0-9\*]{1,31}))$`),
+		groups:  []string{"country", "operator", "outlet"},
+	},
+	{
+		name:    "DIN EVSE ID",
+		grammar: evsedin.Grammar,
+		regex:   regexp.MustCompile(`^(?P<country>\+?([0-9]{1,3}))\*(?P<operator>([0-9]{3,6}))\*(?P<outlet>([0-9\*]{1,32}))$`),
+		groups:  []string{"country", "operator", "outlet"},
+	},
+}
+
+// TestFormats_MatchBaselineRegexps checks that the grammars of the formats accept exactly the inputs matched by their
+// former regular expressions, capturing the same values: the value of a segment is the group nested in the named one,
+// which leaves out the leading '+' of DIN EVSE IDs.
+func TestFormats_MatchBaselineRegexps(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for _, b := range baselines {
+		t.Run(b.name, func(t *testing.T) {
+			for i := 0; i < 20000; i++ {
+				input := grammar.RandomInput(r, b.grammar)
+
+				m, err := b.grammar.Scan(input)
+				var formatErr *c.FormatError
+				scanned := !errors.As(err, &formatErr)
+				groups := b.regex.FindStringSubmatch(input)
+
+				if !assert.Equal(t, groups != nil, scanned, input) || groups == nil || err != nil {
+					continue
+				}
+				for j, name := range b.groups {
+					assert.Equal(t, groups[b.regex.SubexpIndex(name)+1], m.Value(input, j), input)
+				}
+			}
+		})
+	}
+}
+
+func TestFormats_DoNotAllocate(t *testing.T) {
+	for _, f := range formats {
+		t.Run(f.name, func(t *testing.T) {
+			input := []byte(f.input)
+			assert.Nil(t, f.parse(f.input))
+
+			assert.Zero(t, testing.AllocsPerRun(100, func() {
+				_ = f.parse(f.input)
+			}))
+			assert.Zero(t, testing.AllocsPerRun(100, func() {
+				_ = f.checkBytes(input)
+			}))
+			// the string backing the ID can't share the memory of input
+			assert.Equal(t, 1.0, testing.AllocsPerRun(100, func() {
+				_ = f.parseBytes(input)
+			}))
+			// unless the values are read from input
+			assert.Zero(t, testing.AllocsPerRun(100, func() {
+				m, _ := f.grammar.ScanBytes(input)
+				_ = m.Bytes(input, 1)
+			}))
+		})
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	for _, f := range formats {
+		b.Run(f.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = f.parse(f.input)
+			}
+		})
+	}
+}
+
+func BenchmarkParseBytes(b *testing.B) {
+	for _, f := range formats {
+		input := []byte(f.input)
+		b.Run(f.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = f.parseBytes(input)
+			}
+		})
+	}
+}
+
+func BenchmarkCheckBytes(b *testing.B) {
+	for _, f := range formats {
+		input := []byte(f.input)
+		b.Run(f.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = f.checkBytes(input)
+			}
+		})
+	}
+}
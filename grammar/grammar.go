@@ -0,0 +1,431 @@
+package grammar
+
+import (
+	"fmt"
+	"math/rand"
+	"mobilityid/checkdigit"
+	c "mobilityid/common"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Alphabet is the set of characters a segment value can be made of
+type Alphabet int
+
+const (
+	Alphanumeric Alphabet = iota
+	Letters
+	Digits
+)
+
+func (a Alphabet) contains(r rune) bool {
+	switch a {
+	case Letters:
+		return r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z'
+	case Digits:
+		return c.IsDigit(r)
+	default:
+		return c.IsAlphanumeric(r)
+	}
+}
+
+func (a Alphabet) characters() string {
+	switch a {
+	case Letters:
+		return "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	case Digits:
+		return "0123456789"
+	default:
+		return "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	}
+}
+
+func (a Alphabet) class() string {
+	switch a {
+	case Letters:
+		return "A-Za-z"
+	case Digits:
+		return "0-9"
+	default:
+		return "A-Za-z0-9"
+	}
+}
+
+func (a Alphabet) String() string {
+	switch a {
+	case Letters:
+		return "letters"
+	case Digits:
+		return "digits"
+	default:
+		return "letters or digits"
+	}
+}
+
+// Segment describes a field of an identifier
+type Segment struct {
+	Field     c.Field
+	Alphabet  Alphabet
+	Extra     string // characters allowed in the value besides the alphabet, e.g. '*' in EVSE power outlet IDs
+	MinLength int
+	MaxLength int
+	// Marker is a literal preceding the value, which is part of the identifier but not of the field value,
+	// e.g. 'C' before EMI3 instance values; it is matched case-insensitively.
+	Marker         string
+	MarkerOptional bool
+	// Optional marks a segment which may be missing from the input; only the last segment can be optional.
+	Optional bool
+	// Country requires the value to be the ISO 3166-1 alpha-2 code of an existing country, or a code accepted by the
+	// country policy of ParseOptions, if any
+	Country bool
+	// CallingCode requires the value to be an ITU calling code accepted by the country policy of ParseOptions, if any
+	CallingCode bool
+}
+
+// CountryCode is the segment of an ISO 3166-1 alpha-2 country code
+var CountryCode = Segment{
+	Field:     c.FieldCountryCode,
+	Alphabet:  Letters,
+	MinLength: 2,
+	MaxLength: 2,
+	Country:   true,
+}
+
+// CheckDigit is the segment of an optional, single character, check digit
+var CheckDigit = Segment{
+	Field:     c.FieldCheckDigit,
+	Alphabet:  Alphanumeric,
+	MinLength: 1,
+	MaxLength: 1,
+	Optional:  true,
+}
+
+// Grammar describes the syntax of an identifier format, from which its parser, validator, formatter and random
+// generator are derived.
+type Grammar struct {
+	Kind   c.Kind
+	Format c.Format
+	// Separators are the characters accepted between segments; the first one is the canonical one.
+	Separators        string
+	SeparatorRequired bool
+	Segments          []Segment
+	// CheckDigit is the algorithm computing the check digit from the upper case concatenation of markers and values
+	// of all segments but the c.FieldCheckDigit one.
+	CheckDigit checkdigit.Algorithm
+
+	once  sync.Once
+	regex *regexp.Regexp
+}
+
+// Separator returns the canonical separator
+func (g *Grammar) Separator() string {
+	if g.Separators == "" {
+		return ""
+	}
+
+	return g.Separators[:1]
+}
+
+// Index returns the index of the segment of field, or -1 if not found
+func (g *Grammar) Index(field c.Field) int {
+	for i, s := range g.Segments {
+		if s.Field == field {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Regexp returns the regular expression matching the identifiers described by the grammar; each segment value is
+// captured in a group named after its field.
+func (g *Grammar) Regexp() *regexp.Regexp {
+	g.once.Do(func() {
+		g.regex = regexp.MustCompile(g.pattern())
+	})
+
+	return g.regex
+}
+
+func (g *Grammar) pattern() string {
+	var sb strings.Builder
+
+	separator := ""
+	if g.Separators != "" {
+		separator = "[" + quoteClass(g.Separators) + "]"
+		if !g.SeparatorRequired {
+			separator += "?"
+		}
+	}
+
+	sb.WriteString("^")
+	for i, s := range g.Segments {
+		var part strings.Builder
+		if i > 0 {
+			part.WriteString(separator)
+		}
+		if s.Marker != "" {
+			part.WriteString("(?:" + caseInsensitive(s.Marker) + ")")
+			if s.MarkerOptional {
+				part.WriteString("?")
+			}
+		}
+		part.WriteString(fmt.Sprintf("(?P<%s>[%s%s]{%d,%d})", s.Field, s.Alphabet.class(), quoteClass(s.Extra), s.MinLength, s.MaxLength))
+
+		if s.Optional {
+			sb.WriteString("(?:" + part.String() + ")?")
+		} else {
+			sb.WriteString(part.String())
+		}
+	}
+	sb.WriteString("$")
+
+	return sb.String()
+}
+
+// quoteClass escapes characters to be used within a character class
+func quoteClass(characters string) string {
+	return strings.ReplaceAll(regexp.QuoteMeta(characters), "-", "\\-")
+}
+
+func caseInsensitive(literal string) string {
+	var sb strings.Builder
+	for _, r := range literal {
+		upper, lower := strings.ToUpper(string(r)), strings.ToLower(string(r))
+		if upper == lower {
+			sb.WriteString(regexp.QuoteMeta(upper))
+		} else {
+			sb.WriteString("[" + upper + lower + "]")
+		}
+	}
+
+	return sb.String()
+}
+
+// Describe returns a human readable description of the grammar
+func (g *Grammar) Describe() string {
+	parts := make([]string, 0, len(g.Segments))
+	for _, s := range g.Segments {
+		length := fmt.Sprintf("%d", s.MinLength)
+		if s.MinLength != s.MaxLength {
+			length = fmt.Sprintf("%d to %d", s.MinLength, s.MaxLength)
+		}
+
+		part := fmt.Sprintf("%s of %s %s", s.Field, length, s.Alphabet)
+		for _, r := range s.Extra {
+			part += fmt.Sprintf(" or '%c'", r)
+		}
+		if s.Marker != "" {
+			marker := fmt.Sprintf("'%s'", s.Marker)
+			if s.MarkerOptional {
+				marker = "optional " + marker
+			}
+			part = fmt.Sprintf("%s followed by %s", marker, part)
+		}
+		if s.Optional {
+			part = "optional " + part
+		}
+
+		parts = append(parts, part)
+	}
+
+	result := "expected " + strings.Join(parts, ", ")
+	if g.Separators != "" {
+		separators := make([]string, 0, len(g.Separators))
+		for _, r := range g.Separators {
+			separators = append(separators, fmt.Sprintf("'%c'", r))
+		}
+
+		qualifier := "optionally "
+		if g.SeparatorRequired {
+			qualifier = ""
+		}
+		result += fmt.Sprintf(", %sseparated by %s", qualifier, strings.Join(separators, " or "))
+	}
+
+	return result
+}
+
+// Parse parses the input string into the values of the segments, in upper case, and validates them; missing
+// optional segments have an empty value. It returns a *common.FormatError if input doesn't match the grammar, listing
+// the invalid values its separators delimit, if any, or a common.ValidationErrors if any of the values is invalid.
+func (g *Grammar) Parse(input string) ([]string, error) {
+	return g.ParseWith(input, ParseOptions{})
+}
+
+// ParseWith is like Parse, but parses input as strictly as required by opts
+func (g *Grammar) ParseWith(input string, opts ParseOptions) ([]string, error) {
+	m, err := g.ScanWith(input, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]string, len(g.Segments))
+	for i := range g.Segments {
+		values[i] = strings.ToUpper(m.Value(input, i))
+	}
+
+	return values, nil
+}
+
+// Validate validates the values of the segments, in any case, returning a common.ValidationErrors listing every
+// invalid one. The check digit, if present, is only verified if all other values are valid.
+func (g *Grammar) Validate(values []string) error {
+	return g.ValidateWith(values, ParseOptions{})
+}
+
+// ValidateCanonical validates the values of the segments like Validate, but also requires them to be in upper case
+func (g *Grammar) ValidateCanonical(values []string) error {
+	return g.ValidateWith(values, ParseOptions{RejectLowercase: true})
+}
+
+// ValidateWith validates the values of the segments like Validate, as strictly as required by the options of opts
+// which apply to values: RejectLowercase, RequireCheckDigit and Countries.
+func (g *Grammar) ValidateWith(values []string, opts ParseOptions) error {
+	if len(values) != len(g.Segments) {
+		return fmt.Errorf("expected %d values, got %d", len(g.Segments), len(values))
+	}
+
+	var errs c.ValidationErrors
+	for i := range g.Segments {
+		s := &g.Segments[i]
+		errs.Add(s.Field, s.validate(values[i], opts))
+	}
+
+	if len(errs) > 0 || g.CheckDigit == checkdigit.None {
+		return errs.Err()
+	}
+
+	if index := g.Index(c.FieldCheckDigit); index >= 0 && values[index] != "" {
+		actual := rune(strings.ToUpper(values[index])[0])
+		if opts.RejectLowercase {
+			actual = rune(values[index][0])
+		}
+
+		expected, err := g.ComputeCheckDigit(values)
+		if err != nil {
+			return err
+		}
+
+		if actual != expected {
+			errs.Add(c.FieldCheckDigit, &c.FieldError{
+				Field: c.FieldCheckDigit,
+				Value: values[index],
+				Err:   &c.CheckDigitError{Expected: expected, Actual: actual},
+			})
+		}
+	}
+
+	return errs.Err()
+}
+
+// validate returns the first reason why value is not a valid value of the segment, if any, as strictly as required by
+// opts (see ValidateWith)
+func (s *Segment) validate(value string, opts ParseOptions) error {
+	if s.Optional && value == "" {
+		if opts.RequireCheckDigit && s.Field == c.FieldCheckDigit {
+			return &c.FieldError{Field: s.Field, Err: c.ErrRequired}
+		}
+
+		return nil
+	}
+
+	isValid := func(r rune) bool {
+		if opts.RejectLowercase && r >= 'a' && r <= 'z' {
+			return false
+		}
+
+		return s.Alphabet.contains(r) || strings.ContainsRune(s.Extra, r)
+	}
+
+	checks := []error{
+		c.ValidateLength(s.Field, value, s.MinLength, s.MaxLength),
+		c.ValidateCharacters(s.Field, value, isValid),
+	}
+	switch {
+	case s.Country && opts.Countries != nil:
+		checks = append(checks, opts.Countries.Check(value))
+	case s.Country:
+		checks = append(checks, c.ValidateCountryCode(value))
+	case s.CallingCode && opts.Countries != nil:
+		checks = append(checks, opts.Countries.CheckCallingCode(value))
+	}
+
+	return c.FirstError(checks...)
+}
+
+// ComputeCheckDigit computes the check digit from the values of all segments but the check digit one
+func (g *Grammar) ComputeCheckDigit(values []string) (rune, error) {
+	if g.CheckDigit == checkdigit.None {
+		return 0, fmt.Errorf("%v %v IDs have no check digit", g.Format, g.Kind)
+	}
+
+	var sb strings.Builder
+	for i, s := range g.Segments {
+		if s.Field == c.FieldCheckDigit {
+			continue
+		}
+
+		sb.WriteString(strings.ToUpper(s.Marker))
+		sb.WriteString(strings.ToUpper(values[i]))
+	}
+
+	return g.CheckDigit.Compute(sb.String())
+}
+
+// Join returns the string representation of the values of the segments, joined with separator; missing optional
+// segments are left out, markers are always included.
+func (g *Grammar) Join(values []string, separator string) string {
+	var sb strings.Builder
+	for i, s := range g.Segments {
+		if s.Optional && values[i] == "" {
+			continue
+		}
+
+		if i > 0 {
+			sb.WriteString(separator)
+		}
+		sb.WriteString(s.Marker)
+		sb.WriteString(values[i])
+	}
+
+	return sb.String()
+}
+
+// Generate returns random, valid, values of the segments, including the check digit if any
+func (g *Grammar) Generate(r *rand.Rand) []string {
+	values := make([]string, len(g.Segments))
+	for i, s := range g.Segments {
+		if s.Field == c.FieldCheckDigit {
+			continue
+		}
+
+		for {
+			values[i] = generateValue(r, s)
+			if !s.Country || c.IsKnownCountryCode(values[i]) {
+				break
+			}
+		}
+	}
+
+	if index := g.Index(c.FieldCheckDigit); index >= 0 && g.CheckDigit != checkdigit.None {
+		// values are valid by construction, so the check digit can always be computed
+		checkDigit, _ := g.ComputeCheckDigit(values)
+		values[index] = string(checkDigit)
+	}
+
+	return values
+}
+
+func generateValue(r *rand.Rand, s Segment) string {
+	alphabet := s.Alphabet.characters()
+	length := s.MinLength + r.Intn(s.MaxLength-s.MinLength+1)
+
+	value := make([]byte, length)
+	for i := range value {
+		value[i] = alphabet[r.Intn(len(alphabet))]
+	}
+
+	return string(value)
+}
@@ -0,0 +1,110 @@
+package grammar
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"mobilityid/checkdigit"
+	c "mobilityid/common"
+	"testing"
+)
+
+var testGrammar = &Grammar{
+	Kind:       c.KindContract,
+	Format:     c.FormatEmi3,
+	Separators: "-*",
+	Segments: []Segment{
+		CountryCode,
+		{Field: c.FieldInstance, Alphabet: Digits, MinLength: 2, MaxLength: 4, Marker: "C"},
+		CheckDigit,
+	},
+	CheckDigit: checkdigit.Din,
+}
+
+func TestGrammar_Regexp(t *testing.T) {
+	assert.Equal(t, "^(?P<countryCode>[A-Za-z]{2,2})[\\-\\*]?(?:[Cc])(?P<instanceValue>[0-9]{2,4})(?:[\\-\\*]?(?P<checkDigit>[A-Za-z0-9]{1,1}))?$", testGrammar.Regexp().String())
+}
+
+func TestGrammar_Describe(t *testing.T) {
+	assert.Equal(
+		t,
+		"expected countryCode of 2 letters, 'C' followed by instanceValue of 2 to 4 digits, optional checkDigit of 1 letters or digits, optionally separated by '-' or '*'",
+		testGrammar.Describe(),
+	)
+}
+
+func TestGrammar_Parse(t *testing.T) {
+	cases := []struct {
+		name           string
+		input          string
+		expectedValues []string
+		expectedErr    error
+	}{
+		{
+			name:           "parses an input with separators",
+			input:          "nl-c123-4",
+			expectedValues: []string{"NL", "123", "4"},
+		},
+		{
+			name:           "parses an input without separators nor check digit",
+			input:          "NLC1234",
+			expectedValues: []string{"NL", "1234", ""},
+		},
+		{
+			name:        "returns an error if input doesn't match the grammar",
+			input:       "NL-1234",
+			expectedErr: c.ErrInvalidFormat,
+		},
+		{
+			name:        "returns an error if a value is not valid",
+			input:       "ZZ-C123",
+			expectedErr: c.ErrInvalidCountryCode,
+		},
+		{
+			name:        "returns an error if the check digit doesn't match",
+			input:       "NL-C123-7",
+			expectedErr: c.ErrInvalidCheckDigit,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			values, err := testGrammar.Parse(test.input)
+
+			assert.Equal(t, test.expectedValues, values)
+			assert.True(t, errors.Is(err, test.expectedErr))
+		})
+	}
+}
+
+func TestGrammar_ValidateCanonical(t *testing.T) {
+	assert.Nil(t, testGrammar.ValidateCanonical([]string{"NL", "123", "4"}))
+	assert.True(t, errors.Is(testGrammar.ValidateCanonical([]string{"nl", "123", "4"}), c.ErrInvalidCharacter))
+}
+
+func TestGrammar_ValidateWith(t *testing.T) {
+	policy := &c.CountryPolicy{Allowed: []string{"NL", "XK"}, UserAssigned: []c.Country{c.Kosovo}}
+
+	countries := ParseOptions{Countries: policy}
+
+	assert.Nil(t, testGrammar.ValidateWith([]string{"XK", "123", ""}, countries))
+	assert.ErrorIs(t, testGrammar.ValidateWith([]string{"DE", "123", ""}, countries), c.ErrCountryNotAllowed)
+	assert.ErrorIs(t, testGrammar.ValidateWith([]string{"nl", "123", "4"}, Strict), c.ErrInvalidCharacter)
+	assert.ErrorIs(t, testGrammar.ValidateWith([]string{"NL", "123", ""}, Strict), c.ErrRequired)
+}
+
+func TestGrammar_Join(t *testing.T) {
+	assert.Equal(t, "NL-C123-6", testGrammar.Join([]string{"NL", "123", "6"}, testGrammar.Separator()))
+	assert.Equal(t, "NLC123", testGrammar.Join([]string{"NL", "123", ""}, ""))
+}
+
+func TestGrammar_Generate(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 100; i++ {
+		values := testGrammar.Generate(r)
+
+		assert.Nil(t, testGrammar.ValidateCanonical(values))
+		assert.NotEmpty(t, values[2])
+	}
+}
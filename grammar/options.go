@@ -0,0 +1,39 @@
+package grammar
+
+import c "mobilityid/common"
+
+// ParseOptions tune how strictly inputs are matched against a grammar. The zero value is the default, lenient,
+// behaviour of Parse, matching the inputs accepted by Regexp.
+type ParseOptions struct {
+	// TrimSpace ignores ASCII white space surrounding the input
+	TrimSpace bool
+	// RejectLowercase reports lower case letters as invalid characters, instead of upper-casing them
+	RejectLowercase bool
+	// RequireSeparators requires segments to be separated, even in grammars where separators are optional
+	RequireSeparators bool
+	// CanonicalSeparator only accepts the canonical separator of the grammar, e.g. '-' but not '*' in DIN contract IDs
+	CanonicalSeparator bool
+	// RequireMarkers requires optional markers, e.g. the leading '+' of DIN EVSE IDs
+	RequireMarkers bool
+	// RequireCheckDigit requires the check digit, in grammars which have one
+	RequireCheckDigit bool
+	// Countries restricts the accepted country codes, and accepts user-assigned codes, aliases and test codes (see
+	// common.CountryPolicy); any ISO 3166-1 alpha-2 code is accepted if nil. Country codes are kept as they are in
+	// the input, aliases included, so that check digits computed from them remain valid; the country they resolve to
+	// is kept in the Match (see Match.Country).
+	Countries *c.CountryPolicy
+}
+
+// Strict only accepts inputs in canonical form, i.e. as returned by the String method of IDs
+var Strict = ParseOptions{
+	RejectLowercase:    true,
+	RequireSeparators:  true,
+	CanonicalSeparator: true,
+	RequireMarkers:     true,
+	RequireCheckDigit:  true,
+}
+
+// Lenient accepts anything the grammar can make sense of, including surrounding white space
+var Lenient = ParseOptions{
+	TrimSpace: true,
+}
@@ -0,0 +1,54 @@
+package grammar
+
+// Parser parses the identifiers described by a grammar into values of type T, which Build makes from the matched
+// segments. It is shared by the format packages, whose Parse, ParseWith, ParseBytes, Check and CheckBytes functions
+// delegate to it.
+type Parser[T any] struct {
+	Grammar *Grammar
+	// Build returns the identifier made of the values matched in input; it should slice input rather than copy it, so
+	// that parsing doesn't allocate.
+	Build func(input string, m Match) T
+}
+
+// Parse parses input into an identifier, returning the errors of Grammar.Scan. It doesn't allocate if input is valid,
+// unless Build does.
+func (p Parser[T]) Parse(input string) (T, error) {
+	return p.ParseWith(input, ParseOptions{})
+}
+
+// ParseWith parses input like Parse, as strictly as required by opts
+func (p Parser[T]) ParseWith(input string, opts ParseOptions) (T, error) {
+	m, err := p.Grammar.ScanWith(input, opts)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return p.Build(input, m), nil
+}
+
+// ParseBytes parses input like Parse. If input is valid, it allocates once, for the string backing the returned
+// identifier, which can't share the memory of input: use CheckBytes to validate input without allocating, or
+// Grammar.ScanBytes to read its values without allocating, with Match.Bytes.
+func (p Parser[T]) ParseBytes(input []byte) (T, error) {
+	m, err := p.Grammar.ScanBytes(input)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return p.Build(string(input), m), nil
+}
+
+// Check returns the error Parse would return for input, without building the identifier: it doesn't allocate if input
+// is valid.
+func (p Parser[T]) Check(input string) error {
+	_, err := p.Grammar.Scan(input)
+	return err
+}
+
+// CheckBytes is like Check, but takes a byte slice
+func (p Parser[T]) CheckBytes(input []byte) error {
+	_, err := p.Grammar.ScanBytes(input)
+	return err
+}
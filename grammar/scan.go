@@ -0,0 +1,335 @@
+package grammar
+
+import (
+	"fmt"
+	"mobilityid/checkdigit"
+	c "mobilityid/common"
+	"strings"
+)
+
+// MaxSegments is the maximum number of segments of a Grammar
+const MaxSegments = 8
+
+type span struct {
+	start int // start of the segment, including its marker but not the preceding separator
+	value int // start of the value
+	end   int // end of the value
+}
+
+// Match holds the positions of the segments of an input matched by a Grammar. It is a plain value, so that matching
+// doesn't allocate.
+type Match struct {
+	spans   [MaxSegments]span
+	country c.Country
+}
+
+// Value returns the value of the i-th segment, as found in input; it is empty if the segment is missing
+func (m Match) Value(input string, i int) string {
+	return input[m.spans[i].value:m.spans[i].end]
+}
+
+// Bytes returns the value of the i-th segment, as found in input, sharing its memory: with ScanBytes, it reads the
+// values of byte slices without allocating.
+func (m Match) Bytes(input []byte, i int) []byte {
+	return input[m.spans[i].value:m.spans[i].end]
+}
+
+// Bounds returns the start and end offsets of the value of the i-th segment
+func (m Match) Bounds(i int) (start, end int) {
+	return m.spans[i].value, m.spans[i].end
+}
+
+// Country returns the country which the country policy of ParseOptions resolved the country code to, if the code
+// doesn't resolve to it without policy (see common.CountryOf), e.g. an alias, a user-assigned or a test code; it is
+// the zero Country otherwise.
+func (m Match) Country() c.Country {
+	return m.country
+}
+
+// HasMarker returns true if the value of the i-th segment is preceded by its marker in the input
+func (m Match) HasMarker(i int) bool {
+	return m.spans[i].start < m.spans[i].value
+}
+
+// Marked returns the value of the i-th segment preceded by its marker, if present, as found in input
+func (m Match) Marked(input string, i int) string {
+	return input[m.spans[i].start:m.spans[i].end]
+}
+
+// Scan matches input against the grammar and validates its values, like Parse, but returns the positions of the
+// values instead of copying them. It accepts exactly the inputs matched by Regexp, without using regular expressions:
+// it doesn't allocate, unless it returns an error.
+func (g *Grammar) Scan(input string) (Match, error) {
+	return scan(g, input, ParseOptions{})
+}
+
+// ScanBytes is like Scan, but takes a byte slice: it is the allocation free way of parsing byte slices, whose values
+// are read with Match.Bytes or Match.Bounds.
+func (g *Grammar) ScanBytes(input []byte) (Match, error) {
+	return scan(g, input, ParseOptions{})
+}
+
+// ScanWith is like Scan, but matches input as strictly as required by opts; positions are relative to input, even if
+// surrounding white space is trimmed.
+func (g *Grammar) ScanWith(input string, opts ParseOptions) (Match, error) {
+	return scan(g, input, opts)
+}
+
+// ScanBytesWith is like ScanWith, but takes a byte slice
+func (g *Grammar) ScanBytesWith(input []byte, opts ParseOptions) (Match, error) {
+	return scan(g, input, opts)
+}
+
+func scan[T ~string | ~[]byte](g *Grammar, input T, opts ParseOptions) (Match, error) {
+	if len(g.Segments) > MaxSegments {
+		panic(fmt.Sprintf("grammar of %v %v IDs has more than %d segments", g.Format, g.Kind, MaxSegments))
+	}
+
+	start, end := 0, len(input)
+	if opts.TrimSpace {
+		start, end = trimSpace(input)
+	}
+
+	sc := newScanner(g, input[start:end], opts)
+	if !sc.scanSegment(0, 0) {
+		return Match{}, &c.FormatError{
+			Kind:   g.Kind,
+			Format: g.Format,
+			Input:  string(input),
+			Hint:   g.Describe(),
+			Fields: diagnose(g, string(input[start:end]), opts),
+		}
+	}
+
+	m := sc.m
+	for i := range g.Segments {
+		m.spans[i].start += start
+		m.spans[i].value += start
+		m.spans[i].end += start
+	}
+
+	if err := verify(g, input, &m, opts); err != nil {
+		return Match{}, err
+	}
+
+	return m, nil
+}
+
+// trimSpace returns the bounds of input without surrounding ASCII white space
+func trimSpace[T ~string | ~[]byte](input T) (start, end int) {
+	start, end = 0, len(input)
+	for start < end && isSpace(input[start]) {
+		start++
+	}
+	for end > start && isSpace(input[end-1]) {
+		end--
+	}
+
+	return start, end
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r' || b == '\v' || b == '\f'
+}
+
+// scanner matches an input against a grammar, recording the positions of its segments in m
+type scanner[T ~string | ~[]byte] struct {
+	g                 *Grammar
+	input             T
+	separators        string
+	separatorRequired bool
+	markerRequired    bool
+	m                 Match
+}
+
+func newScanner[T ~string | ~[]byte](g *Grammar, input T, opts ParseOptions) scanner[T] {
+	sc := scanner[T]{
+		g:                 g,
+		input:             input,
+		separators:        g.Separators,
+		separatorRequired: g.SeparatorRequired || opts.RequireSeparators,
+		markerRequired:    opts.RequireMarkers,
+	}
+	if opts.CanonicalSeparator {
+		sc.separators = g.Separator()
+	}
+
+	return sc
+}
+
+// scanSegment matches the i-th and following segments from pos to the end of the input. Alternatives are tried in
+// the order of preference of Regexp: separators, markers and optional segments are matched whenever possible, and
+// values are as long as possible.
+func (sc *scanner[T]) scanSegment(i, pos int) bool {
+	if i == len(sc.g.Segments) {
+		return pos == len(sc.input)
+	}
+
+	if sc.scanSeparator(i, pos) {
+		return true
+	}
+
+	if sc.g.Segments[i].Optional {
+		sc.m.spans[i] = span{start: pos, value: pos, end: pos}
+		return sc.scanSegment(i+1, pos)
+	}
+
+	return false
+}
+
+func (sc *scanner[T]) scanSeparator(i, pos int) bool {
+	if i > 0 && sc.separators != "" {
+		if pos < len(sc.input) && strings.IndexByte(sc.separators, sc.input[pos]) >= 0 && sc.scanMarker(i, pos+1) {
+			return true
+		}
+
+		if sc.separatorRequired {
+			return false
+		}
+	}
+
+	return sc.scanMarker(i, pos)
+}
+
+func (sc *scanner[T]) scanMarker(i, pos int) bool {
+	s := &sc.g.Segments[i]
+
+	if s.Marker != "" {
+		if hasPrefixFold(sc.input[pos:], s.Marker) && sc.scanValue(i, pos, pos+len(s.Marker)) {
+			return true
+		}
+
+		if !s.MarkerOptional || sc.markerRequired {
+			return false
+		}
+	}
+
+	return sc.scanValue(i, pos, pos)
+}
+
+func (sc *scanner[T]) scanValue(i, start, pos int) bool {
+	s := &sc.g.Segments[i]
+
+	end := pos
+	for end < len(sc.input) && end-pos < s.MaxLength && s.accepts(sc.input[end]) {
+		end++
+	}
+
+	for ; end-pos >= s.MinLength; end-- {
+		sc.m.spans[i] = span{start: start, value: pos, end: end}
+		if sc.scanSegment(i+1, end) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// accepts returns true if b can be part of the value of the segment, in any case
+func (s *Segment) accepts(b byte) bool {
+	return s.Alphabet.contains(rune(b)) || strings.IndexByte(s.Extra, b) >= 0
+}
+
+func hasPrefixFold[T ~string | ~[]byte](input T, prefix string) bool {
+	if len(input) < len(prefix) {
+		return false
+	}
+
+	for i := 0; i < len(prefix); i++ {
+		if toUpper(input[i]) != toUpper(prefix[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func hasLower[T ~string | ~[]byte](input T) bool {
+	for i := 0; i < len(input); i++ {
+		if input[i] >= 'a' && input[i] <= 'z' {
+			return true
+		}
+	}
+
+	return false
+}
+
+func toUpper(b byte) byte {
+	if b >= 'a' && b <= 'z' {
+		return b - ('a' - 'A')
+	}
+
+	return b
+}
+
+// verify validates what scanning doesn't: case and presence of the check digit when required by opts, country codes,
+// against the country policy of opts if any, and the check digit, which is only verified if all other values are valid.
+func verify[T ~string | ~[]byte](g *Grammar, input T, m *Match, opts ParseOptions) error {
+	var errs c.ValidationErrors
+	for i := range g.Segments {
+		s := &g.Segments[i]
+		segment, value := input[m.spans[i].start:m.spans[i].end], input[m.spans[i].value:m.spans[i].end]
+
+		switch {
+		case opts.RejectLowercase && hasLower(segment):
+			errs.Add(s.Field, &c.FieldError{Field: s.Field, Value: string(segment), Err: c.ErrInvalidCharacter})
+		case opts.RequireCheckDigit && s.Field == c.FieldCheckDigit && len(value) == 0:
+			errs.Add(s.Field, &c.FieldError{Field: s.Field, Err: c.ErrRequired})
+		case s.Country && opts.Countries != nil:
+			country, err := opts.Countries.Resolve(string(value))
+			if err == nil && country != c.CountryOf(string(value)) {
+				m.country = country
+			}
+			errs.Add(s.Field, err)
+		case s.Country && !c.IsKnownCountryCode(value):
+			errs.Add(s.Field, &c.FieldError{Field: s.Field, Value: strings.ToUpper(string(value)), Err: c.ErrUnknownCountry})
+		case s.CallingCode && opts.Countries != nil:
+			// the country has the calling code of value, with its leading '+'
+			country, err := opts.Countries.ResolveCallingCode(string(value))
+			if err == nil && country != c.CountryOf(country.CallingCode) {
+				m.country = country
+			}
+			errs.Add(s.Field, err)
+		}
+	}
+
+	if len(errs) > 0 || g.CheckDigit == checkdigit.None {
+		return errs.Err()
+	}
+
+	index := g.Index(c.FieldCheckDigit)
+	if index < 0 || m.spans[index].value == m.spans[index].end {
+		return nil
+	}
+
+	// the digest keeps the first error, returned by Sum
+	digest := g.CheckDigit.NewDigest()
+	for i := range g.Segments {
+		s := &g.Segments[i]
+		if i == index {
+			continue
+		}
+
+		for j := 0; j < len(s.Marker); j++ {
+			_ = digest.WriteByte(toUpper(s.Marker[j]))
+		}
+		for j := m.spans[i].value; j < m.spans[i].end; j++ {
+			_ = digest.WriteByte(toUpper(input[j]))
+		}
+	}
+
+	expected, err := digest.Sum()
+	if err != nil {
+		return err
+	}
+
+	if actual := rune(toUpper(input[m.spans[index].value])); actual != expected {
+		errs.Add(c.FieldCheckDigit, &c.FieldError{
+			Field: c.FieldCheckDigit,
+			Value: string(actual),
+			Err:   &c.CheckDigitError{Expected: expected, Actual: actual},
+		})
+	}
+
+	return errs.Err()
+}
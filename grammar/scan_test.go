@@ -0,0 +1,397 @@
+package grammar
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	c "mobilityid/common"
+	"strings"
+	"testing"
+)
+
+// dinEvseLikeGrammar exercises required separators which are also allowed within values, and optional markers
+var dinEvseLikeGrammar = &Grammar{
+	Kind:              c.KindEvse,
+	Format:            c.FormatDin,
+	Separators:        "*",
+	SeparatorRequired: true,
+	Segments: []Segment{
+		{
+			Field: c.FieldCountryCode, Alphabet: Digits, MinLength: 1, MaxLength: 3, Marker: "+", MarkerOptional: true,
+			CallingCode: true,
+		},
+		{Field: c.FieldOperatorCode, Alphabet: Digits, MinLength: 3, MaxLength: 6},
+		{Field: c.FieldPowerOutletId, Alphabet: Digits, Extra: "*", MinLength: 1, MaxLength: 8},
+	},
+}
+
+func TestGrammar_Scan(t *testing.T) {
+	cases := []struct {
+		name           string
+		input          string
+		expectedValues []string
+		expectedErr    error
+	}{
+		{
+			name:           "matches an input with separators",
+			input:          "nl-c123-4",
+			expectedValues: []string{"nl", "123", "4"},
+		},
+		{
+			name:           "matches an input without check digit",
+			input:          "NL*C1234",
+			expectedValues: []string{"NL", "1234", ""},
+		},
+		{
+			name:        "returns an error if input doesn't match the grammar",
+			input:       "NL-C1",
+			expectedErr: c.ErrInvalidFormat,
+		},
+		{
+			name:        "returns an error if the country is unknown",
+			input:       "ZZ-C123",
+			expectedErr: c.ErrInvalidCountryCode,
+		},
+		{
+			name:        "returns an error if the check digit doesn't match",
+			input:       "NL-C123-7",
+			expectedErr: c.ErrInvalidCheckDigit,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			m, err := testGrammar.Scan(test.input)
+			mb, errb := testGrammar.ScanBytes([]byte(test.input))
+
+			assert.True(t, errors.Is(err, test.expectedErr))
+			assert.Equal(t, err, errb)
+			assert.Equal(t, m, mb)
+			for i, expected := range test.expectedValues {
+				assert.Equal(t, expected, m.Value(test.input, i))
+				assert.Equal(t, expected, string(mb.Bytes([]byte(test.input), i)))
+			}
+		})
+	}
+}
+
+func TestGrammar_Scan_FormatErrorFields(t *testing.T) {
+	cases := []struct {
+		name           string
+		grammar        *Grammar
+		input          string
+		opts           ParseOptions
+		expectedFields []c.Field
+		expectedErr    error
+	}{
+		{
+			name:           "reports a value of the wrong length",
+			grammar:        testGrammar,
+			input:          "NL-C1",
+			expectedFields: []c.Field{c.FieldInstance},
+			expectedErr:    c.ErrWrongLength,
+		},
+		{
+			name:           "reports a value with invalid characters",
+			grammar:        dinEvseLikeGrammar,
+			input:          "+49*81A*000*438",
+			expectedFields: []c.Field{c.FieldOperatorCode},
+			expectedErr:    c.ErrInvalidCharacter,
+		},
+		{
+			name:           "reports a missing marker",
+			grammar:        testGrammar,
+			input:          "NL-123-7",
+			expectedFields: []c.Field{c.FieldInstance},
+			expectedErr:    c.ErrInvalidFormat,
+		},
+		{
+			name:           "reports every invalid value",
+			grammar:        testGrammar,
+			input:          "ZZ*C12345*78",
+			expectedFields: []c.Field{c.FieldCountryCode, c.FieldInstance, c.FieldCheckDigit},
+			expectedErr:    c.ErrUnknownCountry,
+		},
+		{
+			name:           "validates values as strictly as required by options",
+			grammar:        testGrammar,
+			input:          "nl-C1",
+			opts:           ParseOptions{RejectLowercase: true},
+			expectedFields: []c.Field{c.FieldCountryCode, c.FieldInstance},
+			expectedErr:    c.ErrInvalidCharacter,
+		},
+		{
+			name:        "doesn't report values which separators don't delimit",
+			grammar:     testGrammar,
+			input:       "NLC1",
+			expectedErr: c.ErrInvalidFormat,
+		},
+		{
+			name:        "doesn't report values if they are valid",
+			grammar:     testGrammar,
+			input:       "NL*C123",
+			opts:        ParseOptions{CanonicalSeparator: true},
+			expectedErr: c.ErrInvalidFormat,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := test.grammar.ScanWith(test.input, test.opts)
+			_, errb := test.grammar.ScanBytesWith([]byte(test.input), test.opts)
+
+			var formatErr *c.FormatError
+			assert.True(t, errors.As(err, &formatErr), err)
+			assert.True(t, errors.Is(err, test.expectedErr), err)
+			assert.Equal(t, err, errb)
+			if test.expectedFields == nil {
+				assert.Empty(t, formatErr.Fields)
+				return
+			}
+			assert.Equal(t, test.expectedFields, formatErr.Fields.Fields())
+		})
+	}
+}
+
+func TestGrammar_Scan_Marker(t *testing.T) {
+	m, err := dinEvseLikeGrammar.Scan("+49*810*000*438")
+	assert.Nil(t, err)
+	assert.True(t, m.HasMarker(0))
+	assert.Equal(t, "+49", m.Marked("+49*810*000*438", 0))
+	assert.Equal(t, "000*438", m.Value("+49*810*000*438", 2))
+
+	m, err = dinEvseLikeGrammar.Scan("49*810*000*438")
+	assert.Nil(t, err)
+	assert.False(t, m.HasMarker(0))
+	assert.Equal(t, "49", m.Marked("49*810*000*438", 0))
+}
+
+// TestGrammar_Scan_MatchesRegexp checks that scanning accepts exactly the inputs matched by the regular expression
+// of the grammar, capturing the same values.
+func TestGrammar_Scan_MatchesRegexp(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for _, g := range []*Grammar{testGrammar, dinEvseLikeGrammar} {
+		re := g.Regexp()
+
+		for i := 0; i < 20000; i++ {
+			input := randomInput(r, g)
+
+			sc := newScanner(g, input, ParseOptions{})
+			matched := sc.scanSegment(0, 0)
+			m := sc.m
+			groups := re.FindStringSubmatch(input)
+
+			if !assert.Equal(t, groups != nil, matched, input) || groups == nil {
+				continue
+			}
+			for j, s := range g.Segments {
+				assert.Equal(t, groups[re.SubexpIndex(string(s.Field))], m.Value(input, j), input)
+			}
+		}
+	}
+}
+
+// randomInput returns either a valid input of g, or a random mutation of it, or a random string made of characters
+// that are meaningful to g
+func randomInput(r *rand.Rand, g *Grammar) string {
+	characters := []byte("NLnlCc+0123456789*-" + g.Separators)
+	for _, s := range g.Segments {
+		characters = append(characters, strings.ToLower(s.Marker)+strings.ToUpper(s.Marker)...)
+	}
+
+	values := g.Generate(r)
+	if values[len(values)-1] == "" || r.Intn(2) == 0 {
+		values[len(values)-1] = ""
+	}
+	input := []byte(g.Join(values, g.Separator()))
+
+	switch r.Intn(4) {
+	case 0:
+		return string(input)
+	case 1:
+		input[r.Intn(len(input))] = characters[r.Intn(len(characters))]
+	case 2:
+		at := r.Intn(len(input) + 1)
+		input = append(input[:at], append([]byte{characters[r.Intn(len(characters))]}, input[at:]...)...)
+	default:
+		var sb strings.Builder
+		for j := r.Intn(14); j > 0; j-- {
+			sb.WriteByte(characters[r.Intn(len(characters))])
+		}
+		return sb.String()
+	}
+
+	return string(input)
+}
+
+func TestGrammar_Scan_DoesNotAllocate(t *testing.T) {
+	input := "NL-C123-4"
+	inputBytes := []byte(input)
+
+	assert.Zero(t, testing.AllocsPerRun(100, func() {
+		_, _ = testGrammar.Scan(input)
+	}))
+	assert.Zero(t, testing.AllocsPerRun(100, func() {
+		_, _ = testGrammar.ScanBytes(inputBytes)
+	}))
+}
+
+// BenchmarkGrammar_ParseRegexp measures parsing as it used to be done, with the regular expression of the grammar
+func BenchmarkGrammar_ParseRegexp(b *testing.B) {
+	re := testGrammar.Regexp()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		groups := re.FindStringSubmatch("nl-c123-4")
+		values := make([]string, len(testGrammar.Segments))
+		for j, s := range testGrammar.Segments {
+			values[j] = strings.ToUpper(groups[re.SubexpIndex(string(s.Field))])
+		}
+		_ = testGrammar.Validate(values)
+	}
+}
+
+func BenchmarkGrammar_Scan(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		m, _ := testGrammar.Scan("nl-c123-4")
+		for j := range testGrammar.Segments {
+			_ = m.Value("nl-c123-4", j)
+		}
+	}
+}
+
+func TestGrammar_ScanWith(t *testing.T) {
+	cases := []struct {
+		name        string
+		grammar     *Grammar
+		input       string
+		opts        ParseOptions
+		expectedErr error
+	}{
+		{
+			name:    "accepts surrounding white space if trimmed",
+			grammar: testGrammar,
+			input:   " \tNL-C123-4\n",
+			opts:    ParseOptions{TrimSpace: true},
+		},
+		{
+			name:        "rejects surrounding white space by default",
+			grammar:     testGrammar,
+			input:       " NL-C123-4",
+			expectedErr: c.ErrInvalidFormat,
+		},
+		{
+			name:        "rejects lower case letters",
+			grammar:     testGrammar,
+			input:       "nl-C123-4",
+			opts:        ParseOptions{RejectLowercase: true},
+			expectedErr: c.ErrInvalidCharacter,
+		},
+		{
+			name:        "rejects lower case markers",
+			grammar:     testGrammar,
+			input:       "NL-c123-4",
+			opts:        ParseOptions{RejectLowercase: true},
+			expectedErr: c.ErrInvalidCharacter,
+		},
+		{
+			name:        "requires separators",
+			grammar:     testGrammar,
+			input:       "NLC123-4",
+			opts:        ParseOptions{RequireSeparators: true},
+			expectedErr: c.ErrInvalidFormat,
+		},
+		{
+			name:        "requires the canonical separator",
+			grammar:     testGrammar,
+			input:       "NL*C123-4",
+			opts:        ParseOptions{CanonicalSeparator: true},
+			expectedErr: c.ErrInvalidFormat,
+		},
+		{
+			name:        "requires optional markers",
+			grammar:     dinEvseLikeGrammar,
+			input:       "49*810*000*438",
+			opts:        ParseOptions{RequireMarkers: true},
+			expectedErr: c.ErrInvalidFormat,
+		},
+		{
+			name:        "requires the check digit",
+			grammar:     testGrammar,
+			input:       "NL-C123",
+			opts:        ParseOptions{RequireCheckDigit: true},
+			expectedErr: c.ErrRequired,
+		},
+		{
+			name:    "accepts canonical inputs when strict",
+			grammar: testGrammar,
+			input:   "NL-C123-4",
+			opts:    Strict,
+		},
+		{
+			name:    "accepts canonical inputs with markers when strict",
+			grammar: dinEvseLikeGrammar,
+			input:   "+49*810*000*438",
+			opts:    Strict,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := test.grammar.ScanWith(test.input, test.opts)
+			_, errBytes := test.grammar.ScanBytesWith([]byte(test.input), test.opts)
+
+			if test.expectedErr == nil {
+				assert.Nil(t, err)
+			} else {
+				assert.True(t, errors.Is(err, test.expectedErr), err)
+			}
+			assert.Equal(t, err, errBytes)
+		})
+	}
+}
+
+func TestGrammar_ScanWith_TrimSpace(t *testing.T) {
+	input := "  nl-c123-4 "
+
+	m, err := testGrammar.ScanWith(input, ParseOptions{TrimSpace: true})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "nl", m.Value(input, 0))
+	assert.Equal(t, "123", m.Value(input, 1))
+	assert.Equal(t, "4", m.Value(input, 2))
+}
+
+func TestGrammar_ScanWith_Country(t *testing.T) {
+	policy := &c.CountryPolicy{
+		Allowed:      []string{"GB", "NL", "VA", "XK"},
+		UserAssigned: []c.Country{c.Kosovo},
+		Aliases:      c.Aliases,
+	}
+
+	cases := []struct {
+		grammar  *Grammar
+		input    string
+		expected c.Country
+	}{
+		{testGrammar, "NL-C123", c.Country{}},
+		{testGrammar, "XK-C123", c.Kosovo},
+		{testGrammar, "uk-C123", c.CountryOf("GB")},
+		{dinEvseLikeGrammar, "+39*810*000*438", c.CountryOf("VA")},
+	}
+
+	for _, test := range cases {
+		t.Run(test.input, func(t *testing.T) {
+			m, err := test.grammar.ScanWith(test.input, ParseOptions{Countries: policy})
+
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, m.Country())
+		})
+	}
+
+	m, err := testGrammar.Scan("NL-C123")
+	assert.Nil(t, err)
+	assert.True(t, m.Country().IsZero())
+}
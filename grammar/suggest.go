@@ -0,0 +1,159 @@
+package grammar
+
+import (
+	"mobilityid/checkdigit"
+	"sort"
+)
+
+// EditKind is a kind of typo corrected by a Suggestion
+type EditKind int
+
+const (
+	// Substitution is the replacement of a character by another one
+	Substitution EditKind = iota
+	// Transposition is the swap of two adjacent characters
+	Transposition
+)
+
+func (k EditKind) String() string {
+	if k == Transposition {
+		return "transposition"
+	}
+
+	return "substitution"
+}
+
+// Suggestion is the correction of a single typo, turning an invalid input into a valid one
+type Suggestion struct {
+	// Input is the corrected input
+	Input string
+	Kind  EditKind
+	// Offset is the byte offset of the edit in the original input
+	Offset int
+	// Original are the characters of the original input which have been replaced
+	Original string
+	// Replacement are the characters replacing them
+	Replacement string
+	// Confusable is true if the characters of a substitution look alike, e.g. 'O' and '0'
+	Confusable bool
+}
+
+// confusables lists characters which are easily mistaken for one another, when read or typed
+var confusables = map[byte]string{
+	'0': "ODQ",
+	'O': "0",
+	'D': "0",
+	'Q': "0",
+	'1': "IL",
+	'I': "1",
+	'L': "1",
+	'2': "Z",
+	'Z': "2",
+	'5': "S",
+	'S': "5",
+	'6': "G",
+	'G': "6",
+	'8': "B",
+	'B': "8",
+}
+
+// substitutes are the characters tried in place of each character of the input
+const substitutes = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// Suggest lists the corrections of a single substitution or transposition of adjacent characters which turn input
+// into a valid identifier complete of check digit, most likely first: substitutions of confusable characters, then
+// transpositions, then other substitutions, of characters of the same kind (digits or letters) first.
+//
+// The check digit is what makes corrections meaningful, so Suggest returns nothing for grammars without one, as well
+// as for valid inputs.
+func (g *Grammar) Suggest(input string) []Suggestion {
+	if g.CheckDigit == checkdigit.None {
+		return nil
+	}
+
+	opts := ParseOptions{RequireCheckDigit: true}
+	if _, err := g.ScanWith(input, opts); err == nil {
+		return nil
+	}
+
+	var suggestions []Suggestion
+	seen := map[string]bool{}
+	candidate := []byte(input)
+
+	try := func(s Suggestion) {
+		if _, err := g.ScanBytesWith(candidate, opts); err != nil {
+			return
+		}
+
+		s.Input = string(candidate)
+		if !seen[s.Input] {
+			seen[s.Input] = true
+			suggestions = append(suggestions, s)
+		}
+	}
+
+	for i := 0; i < len(input); i++ {
+		original := toUpper(input[i])
+		if !Alphanumeric.contains(rune(original)) {
+			continue
+		}
+
+		for j := 0; j < len(substitutes); j++ {
+			if substitutes[j] == original {
+				continue
+			}
+
+			candidate[i] = substitutes[j]
+			try(Suggestion{
+				Kind:        Substitution,
+				Offset:      i,
+				Original:    input[i : i+1],
+				Replacement: substitutes[j : j+1],
+				Confusable:  isConfusable(original, substitutes[j]),
+			})
+		}
+		candidate[i] = input[i]
+
+		if i+1 < len(input) && toUpper(input[i+1]) != original && Alphanumeric.contains(rune(input[i+1])) {
+			candidate[i], candidate[i+1] = input[i+1], input[i]
+			try(Suggestion{
+				Kind:        Transposition,
+				Offset:      i,
+				Original:    input[i : i+2],
+				Replacement: string(candidate[i : i+2]),
+			})
+			candidate[i], candidate[i+1] = input[i], input[i+1]
+		}
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].rank() < suggestions[j].rank()
+	})
+
+	return suggestions
+}
+
+// rank orders suggestions by likelihood, lowest first; substituting a digit for a digit, or a letter for a letter, is
+// more likely than mixing them up.
+func (s Suggestion) rank() int {
+	switch {
+	case s.Confusable:
+		return 0
+	case s.Kind == Transposition:
+		return 1
+	case Digits.contains(rune(s.Original[0])) == Digits.contains(rune(s.Replacement[0])):
+		return 2
+	default:
+		return 3
+	}
+}
+
+func isConfusable(a, b byte) bool {
+	for i := 0; i < len(confusables[a]); i++ {
+		if confusables[a][i] == b {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,51 @@
+package grammar
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGrammar_Suggest(t *testing.T) {
+	t.Run("suggests substitutions of confusable characters first", func(t *testing.T) {
+		suggestions := testGrammar.Suggest("NL-C1Z3-4")
+
+		assert.NotEmpty(t, suggestions)
+		assert.Equal(t, Suggestion{
+			Input:       "NL-C123-4",
+			Kind:        Substitution,
+			Offset:      5,
+			Original:    "Z",
+			Replacement: "2",
+			Confusable:  true,
+		}, suggestions[0])
+	})
+
+	t.Run("suggests transpositions of adjacent characters", func(t *testing.T) {
+		suggestions := testGrammar.Suggest("NL-C132-4")
+
+		assert.Contains(t, suggestions, Suggestion{
+			Input:       "NL-C123-4",
+			Kind:        Transposition,
+			Offset:      5,
+			Original:    "32",
+			Replacement: "23",
+		})
+	})
+
+	t.Run("only suggests valid inputs complete of check digit", func(t *testing.T) {
+		for _, s := range testGrammar.Suggest("NL-C123-7") {
+			values, err := testGrammar.ParseWith(s.Input, ParseOptions{RequireCheckDigit: true})
+
+			assert.Nil(t, err)
+			assert.NotEmpty(t, values[2])
+		}
+	})
+
+	t.Run("suggests nothing for valid inputs", func(t *testing.T) {
+		assert.Empty(t, testGrammar.Suggest("NL-C123-4"))
+	})
+
+	t.Run("suggests nothing without check digit", func(t *testing.T) {
+		assert.Empty(t, dinEvseLikeGrammar.Suggest("+49*81O*000*438"))
+	})
+}
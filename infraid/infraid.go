@@ -0,0 +1,167 @@
+// Package infraid implements the eMI3 IDs of charging infrastructure: charging pool IDs, e.g. "NL*TNM*P030123456", and
+// charging station IDs, e.g. "NL*TNM*S030123456", which only differ by their kind, the field of their value and its
+// marker. The poolid and stationid packages instantiate it.
+package infraid
+
+import (
+	"math/rand"
+	c "mobilityid/common"
+	"mobilityid/evseid"
+	"mobilityid/grammar"
+	"strings"
+)
+
+// Scheme describes a kind of charging infrastructure IDs by its grammar, e.g. poolid.Grammar; it is implemented by the
+// empty types which instantiate Id, whose packages parse them with a grammar.Parser building them with FromMatch.
+type Scheme interface {
+	Grammar() *grammar.Grammar
+}
+
+// NewGrammar returns the grammar of the eMI3 IDs of kind, made of a country code, an operator code, and the value of
+// field, preceded by marker, e.g. "NL*TNM*P030123456" for charging pools
+func NewGrammar(kind c.Kind, field c.Field, marker string) *grammar.Grammar {
+	return &grammar.Grammar{
+		Kind:       kind,
+		Format:     c.FormatEmi3,
+		Separators: "*",
+		Segments: []grammar.Segment{
+			grammar.CountryCode,
+			{Field: c.FieldOperatorCode, Alphabet: grammar.Alphanumeric, MinLength: 3, MaxLength: 3},
+			{Field: field, Alphabet: grammar.Alphanumeric, Extra: "*", MinLength: 1, MaxLength: 31, Marker: marker},
+		},
+	}
+}
+
+// Id identifies a charging pool or station, as described by S. IDs are equal (==) if all their fields are, including
+// the country a country policy resolved their country code to, if any (see contractid.Id).
+type Id[S Scheme] struct {
+	countryCode  string
+	operatorCode string
+	value        string
+	// country is the country resolved by the country policy the ID was parsed with, if any (see grammar.Match.Country)
+	country c.Country
+}
+
+// CountryCode returns the country code
+func (id Id[S]) CountryCode() string {
+	return id.countryCode
+}
+
+// OperatorCode returns the operator code
+func (id Id[S]) OperatorCode() string {
+	return id.operatorCode
+}
+
+// Value returns the pool or station ID, without its marker, e.g. "030123456" for "NL*TNM*P030123456"
+func (id Id[S]) Value() string {
+	return id.value
+}
+
+// Kind returns the kind of this identifier
+func (id Id[S]) Kind() c.Kind {
+	return grammarOf[S]().Kind
+}
+
+// Format returns the format of this identifier
+func (id Id[S]) Format() c.Format {
+	return grammarOf[S]().Format
+}
+
+// Country returns the country of the country code, or the zero Country if it is unknown. The country resolved by the
+// country policy the ID was parsed with, if any, is returned for aliases, user-assigned and test codes.
+func (id Id[S]) Country() c.Country {
+	if !id.country.IsZero() {
+		return id.country
+	}
+
+	return c.CountryOf(id.countryCode)
+}
+
+// IsZero returns true if this is the zero value, i.e. none of its fields has been set
+func (id Id[S]) IsZero() bool {
+	return id == Id[S]{}
+}
+
+// PartyId returns the party ID of the operator
+func (id Id[S]) PartyId() string {
+	if id.IsZero() {
+		return ""
+	}
+
+	return id.countryCode + "-" + id.operatorCode
+}
+
+// CompactPartyId returns the party ID of the operator without separator
+func (id Id[S]) CompactPartyId() string {
+	return id.countryCode + id.operatorCode
+}
+
+func (id Id[S]) String() string {
+	if id.IsZero() {
+		return ""
+	}
+
+	g := grammarOf[S]()
+
+	return g.Join(id.values(), g.Separator())
+}
+
+// CompactString returns the ID without separators
+func (id Id[S]) CompactString() string {
+	return strings.ReplaceAll(id.String(), "*", "")
+}
+
+// Contains guesses whether evse belongs to this pool or station: it returns true if evse is operated by the same
+// operator, and if its power outlet ID starts with the value of this ID, followed by '*' or nothing (see
+// evseid.Within). This is only a heuristic, as eMI3 doesn't define how EVSE IDs relate to pool or station IDs: it
+// follows the numbering of many operators, but the EVSEs of others are only known from their data, e.g. OCPI locations.
+func (id Id[S]) Contains(evse evseid.Reader) bool {
+	return !id.IsZero() && evseid.Within(evse, id.countryCode, id.operatorCode, id.value)
+}
+
+// Validate validates the fields of this ID, returning a common.ValidationErrors listing every invalid one.
+func (id Id[S]) Validate() error {
+	return grammarOf[S]().ValidateWith(id.values(), grammar.ParseOptions{
+		RejectLowercase: true,
+		Countries:       c.Accepting(id.countryCode, id.country),
+	})
+}
+
+func (id Id[S]) values() []string {
+	return []string{id.countryCode, id.operatorCode, id.value}
+}
+
+// New returns the ID made of the provided fields, in upper case, if they are valid; returns an error otherwise.
+func New[S Scheme](countryCode, operatorCode, value string) (Id[S], error) {
+	v := []string{countryCode, operatorCode, value}
+	if err := grammarOf[S]().Validate(v); err != nil {
+		return Id[S]{}, err
+	}
+
+	return fromValues[S]([]string{strings.ToUpper(v[0]), strings.ToUpper(v[1]), strings.ToUpper(v[2])}), nil
+}
+
+// Generate returns a random, valid, ID
+func Generate[S Scheme](r *rand.Rand) Id[S] {
+	return fromValues[S](grammarOf[S]().Generate(r))
+}
+
+func grammarOf[S Scheme]() *grammar.Grammar {
+	var scheme S
+	return scheme.Grammar()
+}
+
+func fromValues[S Scheme](v []string) Id[S] {
+	return Id[S]{countryCode: v[0], operatorCode: v[1], value: v[2]}
+}
+
+// FromMatch returns the ID made of the values matched in input by the grammar of S, in upper case, to build the IDs
+// parsed by a grammar.Parser. It doesn't allocate if they already are in upper case.
+func FromMatch[S Scheme](input string, m grammar.Match) Id[S] {
+	return Id[S]{
+		countryCode:  strings.ToUpper(m.Value(input, 0)),
+		operatorCode: strings.ToUpper(m.Value(input, 1)),
+		value:        strings.ToUpper(m.Value(input, 2)),
+		country:      m.Country(),
+	}
+}
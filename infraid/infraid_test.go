@@ -0,0 +1,93 @@
+package infraid
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	c "mobilityid/common"
+	"mobilityid/evseid/iso"
+	"mobilityid/grammar"
+	"testing"
+)
+
+// pool is a scheme of charging pool IDs, as declared by the poolid package
+type pool struct{}
+
+var poolGrammar = NewGrammar(c.KindPool, c.FieldPoolId, "P")
+
+func (pool) Grammar() *grammar.Grammar {
+	return poolGrammar
+}
+
+var parser = grammar.Parser[Id[pool]]{Grammar: poolGrammar, Build: FromMatch[pool]}
+
+func TestNewGrammar(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected []string
+		err      error
+	}{
+		{input: "NL*TNM*P030123456", expected: []string{"NL", "TNM", "030123456"}},
+		{input: "nltnmp03*01", expected: []string{"NL", "TNM", "03*01"}},
+		{input: "NL*TNM*S030123456", err: c.ErrInvalidFormat},
+		{input: "NL*TNM*030123456", err: c.ErrInvalidFormat},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.input, func(t *testing.T) {
+			values, err := poolGrammar.Parse(tc.input)
+
+			if tc.err != nil {
+				assert.True(t, errors.Is(err, tc.err), err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, values)
+		})
+	}
+}
+
+func TestId(t *testing.T) {
+	id, err := New[pool]("nl", "tnm", "030123456")
+	assert.Nil(t, err)
+
+	assert.Equal(t, Id[pool]{countryCode: "NL", operatorCode: "TNM", value: "030123456"}, id)
+	assert.Equal(t, "NL*TNM*P030123456", id.String())
+	assert.Equal(t, "NLTNMP030123456", id.CompactString())
+	assert.Equal(t, "NL-TNM", id.PartyId())
+	assert.Equal(t, c.KindPool, id.Kind())
+	assert.Nil(t, id.Validate())
+}
+
+func TestId_Zero(t *testing.T) {
+	var id Id[pool]
+	evse, _ := iso.Parse("NL*TNM*E030123456*1")
+
+	assert.True(t, id.IsZero())
+	assert.Equal(t, "", id.String())
+	assert.Equal(t, "", id.PartyId())
+	assert.Equal(t, c.KindPool, id.Kind())
+	assert.False(t, id.Contains(evse))
+	assert.NotNil(t, id.Validate())
+}
+
+func TestId_Validate(t *testing.T) {
+	lowercase := Id[pool]{countryCode: "nl", operatorCode: "TNM", value: "1"}
+	invalidValue := Id[pool]{countryCode: "NL", operatorCode: "TNM", value: "0-1"}
+
+	assert.True(t, errors.Is(lowercase.Validate(), c.ErrInvalidCountryCode))
+	assert.True(t, errors.Is(invalidValue.Validate(), c.ErrInvalidPoolId))
+}
+
+func TestFromMatch_KeepsCountry(t *testing.T) {
+	policy := &c.CountryPolicy{UserAssigned: []c.Country{c.Kosovo}}
+
+	id, err := parser.ParseWith("XK*TNM*P1", grammar.ParseOptions{Countries: policy})
+
+	assert.Nil(t, err)
+	assert.Equal(t, c.Kosovo, id.Country())
+	assert.Nil(t, id.Validate())
+
+	_, err = parser.Parse("XK*TNM*P1")
+	assert.True(t, errors.Is(err, c.ErrUnknownCountry), err)
+}
@@ -0,0 +1,203 @@
+// Package normalize cleans up identifiers copied from emails, PDFs or spreadsheets, so that they can be parsed.
+//
+// Normalization is opt-in: parsers never apply it, callers do before parsing, e.g.
+//
+//	normalized, changes := normalize.Normalize(input)
+//	id, err := emi3.Parse(normalized)
+package normalize
+
+import (
+	"fmt"
+	"golang.org/x/text/unicode/norm"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ChangeKind is a kind of change made by Normalize
+type ChangeKind int
+
+const (
+	// Compatibility is the replacement of characters by their NFKC equivalent, e.g. full width letters and digits
+	Compatibility ChangeKind = iota
+	// Dash is the replacement of a dash or hyphen variant by '-', e.g. an en dash
+	Dash
+	// Asterisk is the replacement of an asterisk, bullet or middle dot variant by '*'
+	Asterisk
+	// Invisible is the removal of an invisible character, e.g. a zero width joiner or a soft hyphen
+	Invisible
+	// Space is the removal of white space at either end of the input, or the replacement of a run of white space
+	// within it, e.g. a non-breaking space or several spaces, by a single space. Spaces within an ID are kept, as
+	// removing them could turn it into another valid ID, e.g. "DE*8EO*E000 438" into "DE*8EO*E000438".
+	Space
+	// ListMarker is the removal of a bullet preceding the identifier
+	ListMarker
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case Compatibility:
+		return "compatibility character"
+	case Dash:
+		return "dash"
+	case Asterisk:
+		return "asterisk"
+	case Invisible:
+		return "invisible character"
+	case Space:
+		return "white space"
+	default:
+		return "list marker"
+	}
+}
+
+// Change describes a change made by Normalize
+type Change struct {
+	Kind ChangeKind
+	// Offset is the byte offset of the changed characters in the input
+	Offset int
+	// Original are the changed characters
+	Original string
+	// Replacement are the characters replacing them, or "" if they were removed
+	Replacement string
+}
+
+func (ch Change) String() string {
+	if ch.Replacement == "" {
+		return fmt.Sprintf("removed %v %+q at %d", ch.Kind, ch.Original, ch.Offset)
+	}
+
+	return fmt.Sprintf("replaced %v %+q at %d with %q", ch.Kind, ch.Original, ch.Offset, ch.Replacement)
+}
+
+var (
+	dashes = []rune{
+		'‐', // hyphen
+		'‑', // non-breaking hyphen
+		'‒', // figure dash
+		'–', // en dash
+		'—', // em dash
+		'―', // horizontal bar
+		'⁃', // hyphen bullet
+		'−', // minus sign
+		'➖', // heavy minus sign
+		'⸺', // two-em dash
+		'⸻', // three-em dash
+		'﹘', // small em dash
+	}
+	asterisks = []rune{
+		'·', // middle dot
+		'٭', // arabic five pointed star
+		'•', // bullet
+		'‧', // hyphenation point
+		'⁎', // low asterisk
+		'∗', // asterisk operator
+		'∙', // bullet operator
+		'⋅', // dot operator
+		'✱', // heavy asterisk
+		'✲', // open centre asterisk
+		'・', // katakana middle dot
+	}
+	listMarkers = []rune{
+		'•', // bullet
+		'‣', // triangular bullet
+		'⁃', // hyphen bullet
+		'▪', // black small square
+		'●', // black circle
+		'◦', // white bullet
+	}
+)
+
+// Normalize returns input ready to be parsed, and the changes made to it, in order; no changes are returned if input
+// didn't need any.
+//
+// Characters are folded to their NFKC form, dash and asterisk variants are replaced by '-' and '*', invisible
+// characters are removed, as well as bullets preceding the identifier. White space is removed at both ends, and runs
+// of white space within the input, including the invisible characters they contain, are collapsed to a single space.
+func Normalize(input string) (string, []Change) {
+	var sb strings.Builder
+	var changes []Change
+
+	change := func(kind ChangeKind, offset int, original, replacement string) {
+		sb.WriteString(replacement)
+		changes = append(changes, Change{Kind: kind, Offset: offset, Original: original, Replacement: replacement})
+	}
+
+	// spaceStart is the offset of the pending run of white space, or -1 if there is none
+	spaceStart := -1
+	leading := true
+	endSpace := func(offset int, trailing bool) {
+		if spaceStart < 0 {
+			return
+		}
+
+		run := input[spaceStart:offset]
+		switch {
+		case leading || trailing:
+			change(Space, spaceStart, run, "")
+		case run == " ":
+			sb.WriteString(run)
+		default:
+			change(Space, spaceStart, run, " ")
+		}
+		spaceStart = -1
+	}
+
+	for offset := 0; offset < len(input); {
+		// characters are normalized by segments starting with a character which doesn't combine with previous ones
+		length := norm.NFKC.NextBoundaryInString(input[offset:], true)
+		original := input[offset : offset+length]
+		folded := norm.NFKC.String(original)
+		r, size := utf8.DecodeRuneInString(folded)
+		space := size == len(folded) && unicode.IsSpace(r)
+		invisible := size == len(folded) && unicode.Is(unicode.Cf, r)
+
+		if space || invisible && spaceStart >= 0 {
+			if spaceStart < 0 {
+				spaceStart = offset
+			}
+
+			offset += length
+			continue
+		}
+		endSpace(offset, false)
+
+		switch {
+		case invisible:
+			change(Invisible, offset, original, "")
+		case size == len(folded) && leading && contains(listMarkers, r):
+			change(ListMarker, offset, original, "")
+		case size == len(folded) && contains(dashes, r):
+			change(Dash, offset, original, "-")
+			leading = false
+		case size == len(folded) && contains(asterisks, r):
+			change(Asterisk, offset, original, "*")
+			leading = false
+		case folded != original:
+			change(Compatibility, offset, original, folded)
+			leading = false
+		default:
+			sb.WriteString(original)
+			leading = false
+		}
+
+		offset += length
+	}
+	endSpace(len(input), true)
+
+	if len(changes) == 0 {
+		return input, nil
+	}
+
+	return sb.String(), changes
+}
+
+func contains(runes []rune, r rune) bool {
+	for _, candidate := range runes {
+		if candidate == r {
+			return true
+		}
+	}
+
+	return false
+}
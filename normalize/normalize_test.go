@@ -0,0 +1,120 @@
+package normalize
+
+import (
+	"github.com/stretchr/testify/assert"
+	"mobilityid/contractid/emi3"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	cases := []struct {
+		name            string
+		input           string
+		expected        string
+		expectedChanges []Change
+	}{
+		{
+			name:     "leaves normal input as is",
+			input:    "NL-TNM-C00122045-K",
+			expected: "NL-TNM-C00122045-K",
+		},
+		{
+			name:     "replaces dash variants",
+			input:    "NL–TNM—C00122045‐K",
+			expected: "NL-TNM-C00122045-K",
+			expectedChanges: []Change{
+				{Kind: Dash, Offset: 2, Original: "–", Replacement: "-"},
+				{Kind: Dash, Offset: 8, Original: "—", Replacement: "-"},
+				{Kind: Dash, Offset: 20, Original: "‐", Replacement: "-"},
+			},
+		},
+		{
+			name:     "replaces asterisk variants",
+			input:    "NL∗TNM•E03·0",
+			expected: "NL*TNM*E03*0",
+			expectedChanges: []Change{
+				{Kind: Asterisk, Offset: 2, Original: "∗", Replacement: "*"},
+				{Kind: Asterisk, Offset: 8, Original: "•", Replacement: "*"},
+				{Kind: Asterisk, Offset: 14, Original: "·", Replacement: "*"},
+			},
+		},
+		{
+			name:     "folds full width characters",
+			input:    "ＮＬ-TNM",
+			expected: "NL-TNM",
+			expectedChanges: []Change{
+				{Kind: Compatibility, Offset: 0, Original: "Ｎ", Replacement: "N"},
+				{Kind: Compatibility, Offset: 3, Original: "Ｌ", Replacement: "L"},
+			},
+		},
+		{
+			name:     "removes invisible characters",
+			input:    "NL‍-TNM­",
+			expected: "NL-TNM",
+			expectedChanges: []Change{
+				{Kind: Invisible, Offset: 2, Original: "‍"},
+				{Kind: Invisible, Offset: 9, Original: "­"},
+			},
+		},
+		{
+			name:     "removes white space at both ends",
+			input:    " \tNL*TNM*E03*0\u00a0\n",
+			expected: "NL*TNM*E03*0",
+			expectedChanges: []Change{
+				{Kind: Space, Offset: 0, Original: " \t"},
+				{Kind: Space, Offset: 14, Original: "\u00a0\n"},
+			},
+		},
+		{
+			name:     "keeps single spaces within the input",
+			input:    "DE*8EO*E000 438",
+			expected: "DE*8EO*E000 438",
+		},
+		{
+			name:     "collapses runs of white space within the input, including non-breaking spaces",
+			input:    "+49\u00a0810  000 \u200d 438",
+			expected: "+49 810 000 438",
+			expectedChanges: []Change{
+				{Kind: Space, Offset: 3, Original: "\u00a0", Replacement: " "},
+				{Kind: Space, Offset: 8, Original: "  ", Replacement: " "},
+				{Kind: Space, Offset: 13, Original: " \u200d ", Replacement: " "},
+			},
+		},
+		{
+			name:     "removes bullets preceding the identifier",
+			input:    "• NL*TNM*E03*0",
+			expected: "NL*TNM*E03*0",
+			expectedChanges: []Change{
+				{Kind: ListMarker, Offset: 0, Original: "•"},
+				{Kind: Space, Offset: 3, Original: " "},
+			},
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			actual, changes := Normalize(test.input)
+
+			assert.Equal(t, test.expected, actual)
+			assert.Equal(t, test.expectedChanges, changes)
+		})
+	}
+}
+
+func TestChange_String(t *testing.T) {
+	assert.Equal(t, `replaced dash "\u2013" at 2 with "-"`, Change{Kind: Dash, Offset: 2, Original: "–", Replacement: "-"}.String())
+	assert.Equal(t, `removed invisible character "\u200d" at 2`, Change{Kind: Invisible, Offset: 2, Original: "‍"}.String())
+}
+
+func TestNormalize_BeforeParsing(t *testing.T) {
+	input := "• ＮＬ–TNM–C00122045‍–K "
+
+	_, err := emi3.Parse(input)
+	assert.NotNil(t, err)
+
+	normalized, _ := Normalize(input)
+	id, err := emi3.Parse(normalized)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "NL-TNM-C00122045-K", id.String())
+}
@@ -0,0 +1,87 @@
+package partyid
+
+import (
+	c "mobilityid/common"
+	"mobilityid/contractid"
+	"mobilityid/evseid"
+)
+
+// FromContractId returns the provider which issued id, or the zero Id if id is nil or zero
+func FromContractId(id contractid.Reader) Id {
+	if id == nil || id.IsZero() {
+		return Id{}
+	}
+
+	return Id{
+		countryCode: id.CountryCode(),
+		code:        id.PartyCode(),
+		role:        Provider,
+		format:      c.FormatIso,
+		country:     resolved(id.CountryCode(), id.Country()),
+	}
+}
+
+// FromEvseId returns the operator of id, in the format of id, or the zero Id if id is nil or zero
+func FromEvseId(id evseid.Reader) Id {
+	if id == nil || id.IsZero() {
+		return Id{}
+	}
+
+	format := c.FormatIso
+	if id.Format() == c.FormatDin {
+		format = c.FormatDin
+	}
+
+	return Id{
+		countryCode: id.CountryCode(),
+		code:        id.OperatorCode(),
+		role:        Operator,
+		format:      format,
+		country:     resolved(id.CountryCode(), id.Country()),
+	}
+}
+
+// resolved returns country, the country of an ID resolved by the country policy it was parsed with, if countryCode
+// doesn't resolve to it without policy; it returns the zero Country otherwise, as Id keeps it (see Id.Country).
+func resolved(countryCode string, country c.Country) c.Country {
+	if country != c.CountryOf(countryCode) {
+		return country
+	}
+
+	return c.Country{}
+}
+
+// operated is implemented by the IDs of charging pools and stations
+type operated interface {
+	CountryCode() string
+	OperatorCode() string
+	IsZero() bool
+	c.Identifier
+}
+
+// Of returns the party of any contract, EVSE, charging pool, charging station or party ID: the provider of contract
+// IDs and the operator of the others. It returns false if id is of an unknown kind.
+func Of(id c.Identifier) (Id, bool) {
+	switch v := id.(type) {
+	case Id:
+		return v, true
+	case contractid.Reader:
+		return FromContractId(v), true
+	case evseid.Reader:
+		return FromEvseId(v), true
+	case operated:
+		if v.IsZero() {
+			return Id{}, true
+		}
+
+		return Id{
+			countryCode: v.CountryCode(),
+			code:        v.OperatorCode(),
+			role:        Operator,
+			format:      c.FormatIso,
+			country:     resolved(v.CountryCode(), v.Country()),
+		}, true
+	default:
+		return Id{}, false
+	}
+}
@@ -0,0 +1,102 @@
+package partyid
+
+import (
+	"github.com/stretchr/testify/assert"
+	c "mobilityid/common"
+	contractdin "mobilityid/contractid/din"
+	"mobilityid/contractid/emi3"
+	evsedin "mobilityid/evseid/din"
+	evseiso "mobilityid/evseid/iso"
+	"mobilityid/grammar"
+	"mobilityid/poolid"
+	"mobilityid/stationid"
+	"testing"
+)
+
+func TestOf(t *testing.T) {
+	emi3Id, _ := emi3.Parse("NL-TNM-C00122045-K")
+	dinContractId, _ := contractdin.Parse("NL-TNM-012204-5")
+	isoEvseId, _ := evseiso.Parse("NL*TNM*E03*0")
+	dinEvseId, _ := evsedin.Parse("+49*810*000*438")
+	providerId, _ := ParseProviderId("NL-TNM")
+	poolId, _ := poolid.Parse("NL*TNM*P1")
+	stationId, _ := stationid.Parse("NL*TNM*S1")
+
+	cases := []struct {
+		name     string
+		id       c.Identifier
+		expected string
+		role     Role
+	}{
+		{"EMI3 contract ID", emi3Id, "NL-TNM", Provider},
+		{"DIN contract ID", dinContractId, "NL-TNM", Provider},
+		{"ISO EVSE ID", isoEvseId, "NL*TNM", Operator},
+		{"DIN EVSE ID", dinEvseId, "+49*810", Operator},
+		{"party ID", providerId, "NL-TNM", Provider},
+		{"charging pool ID", poolId, "NL*TNM", Operator},
+		{"charging station ID", stationId, "NL*TNM", Operator},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			party, ok := Of(tc.id)
+
+			assert.True(t, ok)
+			assert.Equal(t, tc.expected, party.String())
+			assert.Equal(t, tc.role, party.Role())
+			assert.Equal(t, tc.id.PartyId(), party.PartyId())
+			assert.Nil(t, party.Validate())
+		})
+	}
+}
+
+func TestOf_Zero(t *testing.T) {
+	party, ok := Of(emi3.ContractId{})
+
+	assert.True(t, ok)
+	assert.True(t, party.IsZero())
+
+	_, ok = Of(nil)
+	assert.False(t, ok)
+}
+
+func TestFromContractId_EqualsParsedProviderId(t *testing.T) {
+	emi3Id, _ := emi3.Parse("NL-TNM-C00122045-K")
+	parsed, _ := ParseProviderId("NLTNM")
+
+	assert.Equal(t, parsed, FromContractId(emi3Id))
+}
+
+func TestDerived_KeepCountryResolvedByPolicy(t *testing.T) {
+	opts := grammar.ParseOptions{Countries: &c.CountryPolicy{UserAssigned: []c.Country{c.Kosovo}}}
+	parser := Parser{Options: opts}
+	provider, _ := parser.ParseProviderId("XK-TNM")
+	operator, _ := parser.ParseOperatorId("XK*TNM")
+
+	contractId, err := emi3.ParseWith("XK-TNM-C00122045", opts)
+	assert.Nil(t, err)
+	evseId, err := evseiso.ParseWith("XK*TNM*E1", opts)
+	assert.Nil(t, err)
+	poolId, err := poolid.ParseWith("XK*TNM*P1", opts)
+	assert.Nil(t, err)
+	poolOperator, ok := Of(poolId)
+	assert.True(t, ok)
+
+	cases := []struct {
+		name     string
+		party    Id
+		expected Id
+	}{
+		{"FromContractId", FromContractId(contractId), provider},
+		{"FromEvseId", FromEvseId(evseId), operator},
+		{"Of", poolOperator, operator},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.party)
+			assert.Equal(t, c.Kosovo, tc.party.Country())
+			assert.Nil(t, tc.party.Validate())
+		})
+	}
+}
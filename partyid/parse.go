@@ -0,0 +1,91 @@
+package partyid
+
+import (
+	c "mobilityid/common"
+	"mobilityid/grammar"
+	"strings"
+)
+
+// Parser parses party IDs as strictly as required by its Options, e.g.
+//
+//	partyid.Parser{Options: grammar.Strict}.ParseProviderId("NL-TNM")
+type Parser struct {
+	Options grammar.ParseOptions
+}
+
+// ParseProviderId parses a provider ID, e.g. "NL-TNM" or "NLTNM"
+func (p Parser) ParseProviderId(input string) (Id, error) {
+	return p.parse(input, Provider, ProviderGrammar)
+}
+
+// ParseOperatorId parses an ISO or EMI3 operator ID, e.g. "NL*TNM" or "NLTNM", or a DIN one, e.g. "+49*810"; its
+// format is returned by Format().
+func (p Parser) ParseOperatorId(input string) (Id, error) {
+	return p.parse(input, Operator, OperatorGrammar, DinOperatorGrammar)
+}
+
+// Parse parses a provider or operator ID: IDs separated by '-', or without separators, are provider IDs, and the ones
+// separated by '*' are operator IDs. Its role is returned by Role().
+func (p Parser) Parse(input string) (Id, error) {
+	id, err := p.ParseProviderId(input)
+	if err == nil {
+		return id, nil
+	}
+
+	id, operatorErr := p.ParseOperatorId(input)
+	if operatorErr == nil {
+		return id, nil
+	}
+
+	noMatch := operatorErr.(*c.NoMatchError)
+	noMatch.Rejections = append([]c.Rejection{{Format: ProviderGrammar.Format, Err: err}}, noMatch.Rejections...)
+
+	return Id{}, noMatch
+}
+
+// parse tries grammars in order, returning a *common.NoMatchError if input doesn't match any, or the error of the
+// single grammar tried
+func (p Parser) parse(input string, role Role, grammars ...*grammar.Grammar) (Id, error) {
+	var rejections []c.Rejection
+
+	for _, g := range grammars {
+		m, err := g.ScanWith(input, p.Options)
+		if err == nil {
+			return fromMatch(g, role, input, m), nil
+		}
+
+		rejections = append(rejections, c.Rejection{Format: g.Format, Err: err})
+	}
+
+	if len(grammars) == 1 {
+		return Id{}, rejections[0].Err
+	}
+
+	return Id{}, &c.NoMatchError{Kind: c.KindParty, Input: input, Rejections: rejections}
+}
+
+// fromMatch returns the ID matched in input, in upper case, reusing the leading '+' of DIN country codes if present
+func fromMatch(g *grammar.Grammar, role Role, input string, m grammar.Match) Id {
+	id := fromValues(g, role, strings.ToUpper(m.Value(input, 0)), strings.ToUpper(m.Value(input, 1)))
+	if g == DinOperatorGrammar && m.HasMarker(0) {
+		id.countryCode = m.Marked(input, 0)
+	}
+	id.country = m.Country()
+
+	return id
+}
+
+// ParseProviderId parses a provider ID, e.g. "NL-TNM" or "NLTNM" (see Parser.ParseProviderId)
+func ParseProviderId(input string) (Id, error) {
+	return Parser{}.ParseProviderId(input)
+}
+
+// ParseOperatorId parses an ISO, EMI3 or DIN operator ID, e.g. "NL*TNM" or "+49*810" (see Parser.ParseOperatorId)
+func ParseOperatorId(input string) (Id, error) {
+	return Parser{}.ParseOperatorId(input)
+}
+
+// Parse parses a provider or operator ID (see Parser.Parse)
+func Parse(input string) (Id, error) {
+	return Parser{}.Parse(input)
+}
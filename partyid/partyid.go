@@ -0,0 +1,212 @@
+// Package partyid parses, validates and formats the identifiers of parties: the provider IDs of eMobility service
+// providers (e.g. "NL-TNM"), which are part of contract IDs, and the operator IDs of charge point operators, which are
+// part of EVSE IDs, in ISO/EMI3 (e.g. "NL*TNM") or DIN (e.g. "+49*810") format.
+package partyid
+
+import (
+	c "mobilityid/common"
+	"mobilityid/grammar"
+	"strings"
+)
+
+// Role is the role of a party
+type Role int
+
+const (
+	// Provider is an eMobility service provider, issuing contract IDs
+	Provider Role = iota + 1
+	// Operator is a charge point operator, operating EVSEs
+	Operator
+)
+
+// String returns the name of the role
+func (r Role) String() string {
+	switch r {
+	case Provider:
+		return "provider"
+	case Operator:
+		return "operator"
+	default:
+		return "unknown"
+	}
+}
+
+// ProviderGrammar describes the syntax of provider IDs, e.g. "NL-TNM" or "NLTNM"
+var ProviderGrammar = &grammar.Grammar{
+	Kind:       c.KindParty,
+	Format:     c.FormatIso,
+	Separators: "-",
+	Segments: []grammar.Segment{
+		grammar.CountryCode,
+		{Field: c.FieldPartyCode, Alphabet: grammar.Alphanumeric, MinLength: 3, MaxLength: 3},
+	},
+}
+
+// OperatorGrammar describes the syntax of ISO and EMI3 operator IDs, e.g. "NL*TNM" or "NLTNM"
+var OperatorGrammar = &grammar.Grammar{
+	Kind:       c.KindParty,
+	Format:     c.FormatIso,
+	Separators: "*",
+	Segments: []grammar.Segment{
+		grammar.CountryCode,
+		{Field: c.FieldOperatorCode, Alphabet: grammar.Alphanumeric, MinLength: 3, MaxLength: 3},
+	},
+}
+
+// DinOperatorGrammar describes the syntax of DIN operator IDs, e.g. "+49*810"; the leading '+' of the country code is
+// optional when parsing.
+var DinOperatorGrammar = &grammar.Grammar{
+	Kind:              c.KindParty,
+	Format:            c.FormatDin,
+	Separators:        "*",
+	SeparatorRequired: true,
+	Segments: []grammar.Segment{
+		{Field: c.FieldCountryCode, Alphabet: grammar.Digits, MinLength: 1, MaxLength: 3, Marker: "+", MarkerOptional: true, CallingCode: true},
+		{Field: c.FieldOperatorCode, Alphabet: grammar.Digits, MinLength: 3, MaxLength: 6},
+	},
+}
+
+// Id identifies a party: a provider, or an operator in ISO or DIN format.
+type Id struct {
+	countryCode string
+	code        string
+	role        Role
+	format      c.Format
+	// country is the country resolved by the country policy the ID was parsed with, if any (see grammar.Match.Country);
+	// like the other fields, it is compared by ==.
+	country c.Country
+}
+
+// CountryCode returns the country code, which is the ITU calling code of DIN operator IDs, e.g. "+49"
+func (id Id) CountryCode() string {
+	return id.countryCode
+}
+
+// Code returns the party code of providers, or the operator code of operators
+func (id Id) Code() string {
+	return id.code
+}
+
+// Role returns the role of the party
+func (id Id) Role() Role {
+	return id.role
+}
+
+// Kind returns the kind of this identifier
+func (id Id) Kind() c.Kind {
+	return c.KindParty
+}
+
+// Format returns the format of this identifier: DIN for DIN operator IDs, ISO otherwise
+func (id Id) Format() c.Format {
+	return id.format
+}
+
+// Country returns the country of the country code, which is the primary country of a calling code shared by many
+// countries, or the zero Country if it is unknown (see common.CountryOf). The country resolved by the country policy
+// the ID was parsed with, if any, is returned for aliases, user-assigned and test codes.
+func (id Id) Country() c.Country {
+	if !id.country.IsZero() {
+		return id.country
+	}
+
+	return c.CountryOf(id.countryCode)
+}
+
+// IsZero returns true if this is the zero value, i.e. none of its fields has been set
+func (id Id) IsZero() bool {
+	return id == Id{}
+}
+
+// PartyId returns the party ID, as returned by the PartyId method of contract and EVSE IDs, e.g. "NL-TNM"
+func (id Id) PartyId() string {
+	if id.IsZero() {
+		return ""
+	}
+
+	return id.countryCode + "-" + id.code
+}
+
+// CompactPartyId returns the party ID without separator
+func (id Id) CompactPartyId() string {
+	return id.countryCode + id.code
+}
+
+// String returns the canonical representation of the ID: "NL-TNM" for providers, "NL*TNM" or "+49*810" for operators
+func (id Id) String() string {
+	if id.IsZero() {
+		return ""
+	}
+
+	return id.countryCode + id.grammar().Separator() + id.code
+}
+
+// CompactString returns the ID without separator, e.g. "NLTNM"
+func (id Id) CompactString() string {
+	return id.countryCode + id.code
+}
+
+// Validate validates the fields of this ID, returning a common.ValidationErrors listing every invalid one.
+func (id Id) Validate() error {
+	if id.role != Provider && id.role != Operator {
+		return c.ValidationErrors{{Field: c.FieldPartyCode, Value: id.code, Err: c.ErrInvalidFormat}}
+	}
+
+	countryCode := id.countryCode
+	if id.format == c.FormatDin && countryCode != "" {
+		if !strings.HasPrefix(countryCode, "+") {
+			return c.ValidationErrors{{Field: c.FieldCountryCode, Value: countryCode, Err: c.ErrInvalidCharacter}}
+		}
+
+		countryCode = countryCode[1:]
+	}
+
+	return id.grammar().ValidateWith([]string{countryCode, id.code}, grammar.ParseOptions{
+		RejectLowercase: true,
+		Countries:       c.Accepting(id.countryCode, id.country),
+	})
+}
+
+func (id Id) grammar() *grammar.Grammar {
+	switch {
+	case id.role == Provider:
+		return ProviderGrammar
+	case id.format == c.FormatDin:
+		return DinOperatorGrammar
+	default:
+		return OperatorGrammar
+	}
+}
+
+// NewProviderId returns the ID of a provider, e.g. "NL-TNM", if provided input is valid; returns an error otherwise.
+func NewProviderId(countryCode, partyCode string) (Id, error) {
+	return newId(ProviderGrammar, Provider, countryCode, partyCode)
+}
+
+// NewOperatorId returns the ISO ID of an operator, e.g. "NL*TNM", if provided input is valid; returns an error
+// otherwise.
+func NewOperatorId(countryCode, operatorCode string) (Id, error) {
+	return newId(OperatorGrammar, Operator, countryCode, operatorCode)
+}
+
+// NewDinOperatorId returns the DIN ID of an operator, e.g. "+49*810", if provided input is valid; returns an error
+// otherwise. The leading '+' of the country code is optional.
+func NewDinOperatorId(countryCode, operatorCode string) (Id, error) {
+	return newId(DinOperatorGrammar, Operator, strings.TrimPrefix(countryCode, "+"), operatorCode)
+}
+
+func newId(g *grammar.Grammar, role Role, countryCode, code string) (Id, error) {
+	if err := g.Validate([]string{countryCode, code}); err != nil {
+		return Id{}, err
+	}
+
+	return fromValues(g, role, strings.ToUpper(countryCode), strings.ToUpper(code)), nil
+}
+
+func fromValues(g *grammar.Grammar, role Role, countryCode, code string) Id {
+	if g == DinOperatorGrammar {
+		countryCode = "+" + countryCode
+	}
+
+	return Id{countryCode: countryCode, code: code, role: role, format: g.Format}
+}
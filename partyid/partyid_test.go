@@ -0,0 +1,161 @@
+package partyid
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	c "mobilityid/common"
+	"mobilityid/grammar"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		input   string
+		role    Role
+		format  c.Format
+		country string
+		code    string
+		output  string
+	}{
+		{"NL-TNM", Provider, c.FormatIso, "NL", "TNM", "NL-TNM"},
+		{"NLTNM", Provider, c.FormatIso, "NL", "TNM", "NL-TNM"},
+		{"nl-tnm", Provider, c.FormatIso, "NL", "TNM", "NL-TNM"},
+		{"NL*TNM", Operator, c.FormatIso, "NL", "TNM", "NL*TNM"},
+		{"+49*810", Operator, c.FormatDin, "+49", "810", "+49*810"},
+		{"49*810", Operator, c.FormatDin, "+49", "810", "+49*810"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.input, func(t *testing.T) {
+			id, err := Parse(tc.input)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.role, id.Role())
+			assert.Equal(t, tc.format, id.Format())
+			assert.Equal(t, tc.country, id.CountryCode())
+			assert.Equal(t, tc.code, id.Code())
+			assert.Equal(t, tc.output, id.String())
+			assert.Nil(t, id.Validate())
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	cases := []struct {
+		input string
+		err   error
+	}{
+		{"", c.ErrInvalidFormat},
+		{"NL-TN", c.ErrInvalidFormat},
+		{"NL-TNM*", c.ErrInvalidFormat},
+		{"ZZ-TNM", c.ErrUnknownCountry},
+		{"ZZ*TNM", c.ErrUnknownCountry},
+		{"+49*81", c.ErrInvalidFormat},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.input, func(t *testing.T) {
+			_, err := Parse(tc.input)
+
+			var noMatch *c.NoMatchError
+			assert.True(t, errors.As(err, &noMatch))
+			assert.True(t, errors.Is(noMatch.Rejections[0].Err, tc.err) || errors.Is(noMatch.Rejections[1].Err, tc.err), err)
+		})
+	}
+}
+
+func TestParseProviderId(t *testing.T) {
+	id, err := ParseProviderId("NLTNM")
+	assert.Nil(t, err)
+	assert.Equal(t, Provider, id.Role())
+
+	_, err = ParseProviderId("NL*TNM")
+	assert.True(t, errors.Is(err, c.ErrInvalidFormat))
+}
+
+func TestParseOperatorId(t *testing.T) {
+	id, err := ParseOperatorId("NLTNM")
+	assert.Nil(t, err)
+	assert.Equal(t, Operator, id.Role())
+	assert.Equal(t, "NL*TNM", id.String())
+
+	_, err = ParseOperatorId("NL-TNM")
+	assert.NotNil(t, err)
+}
+
+func TestParser_CountryPolicy(t *testing.T) {
+	parser := Parser{Options: grammar.ParseOptions{Countries: &c.CountryPolicy{Allowed: c.EEA}}}
+
+	_, err := parser.Parse("NL-TNM")
+	assert.Nil(t, err)
+
+	_, err = parser.ParseProviderId("US-TNM")
+	assert.True(t, errors.Is(err, c.ErrCountryNotAllowed))
+
+	_, err = parser.ParseOperatorId("+1*810")
+	var noMatch *c.NoMatchError
+	assert.True(t, errors.As(err, &noMatch))
+	assert.True(t, errors.Is(noMatch.Rejections[1].Err, c.ErrCountryNotAllowed))
+}
+
+func TestParser_CountryPolicy_KeepsCountry(t *testing.T) {
+	parser := Parser{Options: grammar.ParseOptions{Countries: &c.CountryPolicy{Aliases: c.Aliases}}}
+
+	id, err := parser.ParseOperatorId("UK*TNM")
+	assert.Nil(t, err)
+
+	assert.Equal(t, "GB", id.Country().Alpha2)
+	assert.Nil(t, id.Validate())
+}
+
+func TestNewIds(t *testing.T) {
+	provider, err := NewProviderId("nl", "tnm")
+	assert.Nil(t, err)
+	assert.Equal(t, "NL-TNM", provider.String())
+
+	operator, err := NewOperatorId("NL", "TNM")
+	assert.Nil(t, err)
+	assert.Equal(t, "NL*TNM", operator.String())
+	assert.NotEqual(t, provider, operator)
+
+	dinOperator, err := NewDinOperatorId("49", "810")
+	assert.Nil(t, err)
+	assert.Equal(t, "+49*810", dinOperator.String())
+	assert.Equal(t, "+49810", dinOperator.CompactString())
+
+	_, err = NewProviderId("ZZ", "TNM")
+	assert.True(t, errors.Is(err, c.ErrUnknownCountry))
+
+	_, err = NewDinOperatorId("+49", "81")
+	assert.True(t, errors.Is(err, c.ErrInvalidOperatorCode))
+}
+
+func TestId_Identifier(t *testing.T) {
+	var id c.Identifier
+	id, _ = NewProviderId("NL", "TNM")
+
+	assert.Equal(t, c.KindParty, id.Kind())
+	assert.Equal(t, c.FormatIso, id.Format())
+	assert.Equal(t, "NL-TNM", id.PartyId())
+	assert.Equal(t, "NLTNM", id.CompactString())
+	assert.Equal(t, "NLD", id.Country().Alpha3)
+}
+
+func TestId_Validate(t *testing.T) {
+	assert.NotNil(t, Id{}.Validate())
+	assert.NotNil(t, Id{countryCode: "49", code: "810", role: Operator, format: c.FormatDin}.Validate())
+	assert.True(t, errors.Is(Id{code: "810", role: Operator, format: c.FormatDin}.Validate(), c.ErrRequired))
+	assert.NotNil(t, Id{countryCode: "nl", code: "TNM", role: Provider, format: c.FormatIso}.Validate())
+}
+
+func TestId_IsZero(t *testing.T) {
+	assert.True(t, Id{}.IsZero())
+	assert.Equal(t, "", Id{}.String())
+	assert.Equal(t, "", Id{}.PartyId())
+}
+
+func TestRole_String(t *testing.T) {
+	assert.Equal(t, "provider", Provider.String())
+	assert.Equal(t, "operator", Operator.String())
+	assert.Equal(t, "unknown", Role(0).String())
+}
@@ -0,0 +1,61 @@
+// Package poolid parses, validates and formats EMI3 charging pool IDs, e.g. "NL*TNM*P030123456"
+package poolid
+
+import (
+	"math/rand"
+	c "mobilityid/common"
+	"mobilityid/grammar"
+	"mobilityid/infraid"
+)
+
+// Grammar describes the syntax of EMI3 charging pool IDs, e.g. "NL*TNM*P030123456"
+var Grammar = infraid.NewGrammar(c.KindPool, c.FieldPoolId, "P")
+
+// pool is the scheme of charging pool IDs
+type pool struct{}
+
+func (pool) Grammar() *grammar.Grammar {
+	return Grammar
+}
+
+// parser parses charging pool IDs, as described by Grammar
+var parser = grammar.Parser[PoolId]{Grammar: Grammar, Build: infraid.FromMatch[pool]}
+
+// PoolId represents an EMI3 charging pool ID; its Value is the pool ID, without the leading 'P'.
+type PoolId = infraid.Id[pool]
+
+// NewPoolId returns a PoolId, if provided input is valid; returns an error otherwise.
+func NewPoolId(countryCode, operatorCode, poolId string) (PoolId, error) {
+	return infraid.New[pool](countryCode, operatorCode, poolId)
+}
+
+// Parse parses the input string into a PoolId, if it is valid; returns an error otherwise.
+// It doesn't allocate if input is valid and in upper case.
+func Parse(input string) (PoolId, error) {
+	return ParseWith(input, grammar.ParseOptions{})
+}
+
+// ParseWith parses input like Parse, as strictly as required by opts
+func ParseWith(input string, opts grammar.ParseOptions) (PoolId, error) {
+	return parser.ParseWith(input, opts)
+}
+
+// ParseBytes parses input like Parse; it allocates once, if input is valid (see grammar.Parser.ParseBytes)
+func ParseBytes(input []byte) (PoolId, error) {
+	return parser.ParseBytes(input)
+}
+
+// Check returns the error Parse would return for input, without building the ID: it doesn't allocate if input is valid.
+func Check(input string) error {
+	return parser.Check(input)
+}
+
+// CheckBytes is like Check, but takes a byte slice
+func CheckBytes(input []byte) error {
+	return parser.CheckBytes(input)
+}
+
+// Generate returns a random, valid, PoolId
+func Generate(r *rand.Rand) PoolId {
+	return infraid.Generate[pool](r)
+}
@@ -0,0 +1,61 @@
+// Package stationid parses, validates and formats EMI3 charging station IDs, e.g. "NL*TNM*S030123456"
+package stationid
+
+import (
+	"math/rand"
+	c "mobilityid/common"
+	"mobilityid/grammar"
+	"mobilityid/infraid"
+)
+
+// Grammar describes the syntax of EMI3 charging station IDs, e.g. "NL*TNM*S030123456"
+var Grammar = infraid.NewGrammar(c.KindStation, c.FieldStationId, "S")
+
+// station is the scheme of charging station IDs
+type station struct{}
+
+func (station) Grammar() *grammar.Grammar {
+	return Grammar
+}
+
+// parser parses charging station IDs, as described by Grammar
+var parser = grammar.Parser[StationId]{Grammar: Grammar, Build: infraid.FromMatch[station]}
+
+// StationId represents an EMI3 charging station ID; its Value is the station ID, without the leading 'S'.
+type StationId = infraid.Id[station]
+
+// NewStationId returns a StationId, if provided input is valid; returns an error otherwise.
+func NewStationId(countryCode, operatorCode, stationId string) (StationId, error) {
+	return infraid.New[station](countryCode, operatorCode, stationId)
+}
+
+// Parse parses the input string into a StationId, if it is valid; returns an error otherwise.
+// It doesn't allocate if input is valid and in upper case.
+func Parse(input string) (StationId, error) {
+	return ParseWith(input, grammar.ParseOptions{})
+}
+
+// ParseWith parses input like Parse, as strictly as required by opts
+func ParseWith(input string, opts grammar.ParseOptions) (StationId, error) {
+	return parser.ParseWith(input, opts)
+}
+
+// ParseBytes parses input like Parse; it allocates once, if input is valid (see grammar.Parser.ParseBytes)
+func ParseBytes(input []byte) (StationId, error) {
+	return parser.ParseBytes(input)
+}
+
+// Check returns the error Parse would return for input, without building the ID: it doesn't allocate if input is valid.
+func Check(input string) error {
+	return parser.Check(input)
+}
+
+// CheckBytes is like Check, but takes a byte slice
+func CheckBytes(input []byte) error {
+	return parser.CheckBytes(input)
+}
+
+// Generate returns a random, valid, StationId
+func Generate(r *rand.Rand) StationId {
+	return infraid.Generate[station](r)
+}
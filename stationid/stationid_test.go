@@ -0,0 +1,147 @@
+package stationid
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	c "mobilityid/common"
+	"mobilityid/evseid/iso"
+	"mobilityid/grammar"
+	"testing"
+)
+
+func mustNew(countryCode, operatorCode, stationId string) StationId {
+	id, err := NewStationId(countryCode, operatorCode, stationId)
+	if err != nil {
+		panic(err)
+	}
+
+	return id
+}
+
+var expectedId = mustNew("NL", "TNM", "030123456")
+
+func TestGrammar(t *testing.T) {
+	assert.Equal(t, c.KindStation, Grammar.Kind)
+	assert.Equal(t, c.FormatEmi3, Grammar.Format)
+	assert.Equal(t, 2, Grammar.Index(c.FieldStationId))
+	assert.Equal(t, "S", Grammar.Segments[2].Marker)
+
+	values, err := Grammar.Parse("NL*TNM*S030123456")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"NL", "TNM", "030123456"}, values)
+}
+
+func TestStationId_String(t *testing.T) {
+	assert.Equal(t, "NL*TNM*S030123456", expectedId.String())
+	assert.Equal(t, "NLTNMS030123456", expectedId.CompactString())
+	assert.Equal(t, "030123456", expectedId.Value())
+	assert.Equal(t, "", StationId{}.String())
+}
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected StationId
+		err      error
+	}{
+		{input: "NL*TNM*S030123456", expected: expectedId},
+		{input: "NLTNMS030123456", expected: expectedId},
+		{input: "nl*tnm*s030123456", expected: expectedId},
+		{input: "NL*TNM*S03*01", expected: mustNew("NL", "TNM", "03*01")},
+		{input: "NL*TNM*P030123456", err: c.ErrInvalidFormat},
+		{input: "NL*TNM*E030123456", err: c.ErrInvalidFormat},
+		{input: "ZZ*TNM*S030123456", err: c.ErrUnknownCountry},
+		{input: "NL*TNM*S", err: c.ErrInvalidFormat},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.input, func(t *testing.T) {
+			id, err := Parse(tc.input)
+
+			if tc.err != nil {
+				assert.True(t, errors.Is(err, tc.err), err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, id)
+		})
+	}
+}
+
+func TestParseWith_CountryPolicy(t *testing.T) {
+	policy := &c.CountryPolicy{UserAssigned: []c.Country{c.Kosovo}}
+	id, err := ParseWith("XK*TNM*S1", grammar.ParseOptions{Countries: policy})
+
+	assert.Nil(t, err)
+	assert.Equal(t, c.Kosovo, id.Country())
+	assert.Nil(t, id.Validate())
+}
+
+func TestNewStationId(t *testing.T) {
+	id, err := NewStationId("nl", "tnm", "030123456")
+	assert.Nil(t, err)
+	assert.Equal(t, expectedId, id)
+
+	_, err = NewStationId("NL", "TNMX", "0301*")
+	var errs c.ValidationErrors
+	assert.True(t, errors.As(err, &errs))
+	assert.Equal(t, []c.Field{c.FieldOperatorCode}, errs.Fields())
+
+	_, err = NewStationId("NL", "TNM", "03-01")
+	assert.True(t, errors.Is(err, c.ErrInvalidStationId))
+}
+
+func TestStationId_Identifier(t *testing.T) {
+	var id c.Identifier = expectedId
+
+	assert.Equal(t, c.KindStation, id.Kind())
+	assert.Equal(t, c.FormatEmi3, id.Format())
+	assert.Equal(t, "NL-TNM", id.PartyId())
+	assert.Equal(t, "NLD", id.Country().Alpha3)
+}
+
+func TestStationId_Validate(t *testing.T) {
+	assert.Nil(t, expectedId.Validate())
+	assert.NotNil(t, StationId{}.Validate())
+}
+
+func TestStationId_Contains(t *testing.T) {
+	evse, _ := iso.Parse("NL*TNM*E030123456*1")
+	other, _ := iso.Parse("NL*TNM*E040123456*1")
+	otherOperator, _ := iso.Parse("NL*ABC*E030123456*1")
+
+	assert.True(t, expectedId.Contains(evse))
+	assert.False(t, expectedId.Contains(other))
+	assert.False(t, expectedId.Contains(otherOperator))
+	assert.False(t, StationId{}.Contains(evse))
+}
+
+func TestGenerate(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		id := Generate(r)
+
+		parsed, err := Parse(id.String())
+		assert.Nil(t, err)
+		assert.Equal(t, id, parsed)
+	}
+}
+
+func TestParseBytes(t *testing.T) {
+	id, err := ParseBytes([]byte("NL*TNM*S030123456"))
+
+	assert.Nil(t, err)
+	assert.Equal(t, expectedId, id)
+	assert.Nil(t, CheckBytes([]byte("NL*TNM*S030123456")))
+	assert.NotNil(t, Check("NL*TNM*030123456"))
+}
+
+func TestParse_DoesNotAllocate(t *testing.T) {
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = Parse("NL*TNM*S030123456")
+	})
+
+	assert.Zero(t, allocs)
+}